@@ -0,0 +1,237 @@
+package grobotstxt
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// patternSet is a set of Allow and Disallow patterns, compiled into a trie
+// that is evaluated as a nondeterministic automaton, so that all patterns
+// are matched in a single pass over the path.
+//
+// Each edge of the trie is a byte of a pattern, or a '*', which loops on
+// every byte of the path. A trailing '$' marks a pattern that only matches
+// at the end of the path. Since patterns are anchored only at the start of
+// the path, any other pattern matches as soon as its node is reached.
+//
+// A patternSet gives the same priorities as LongestMatchStrategy, for which
+// a pattern's priority is its length, and so depends only on its node.
+// It is immutable, and is safe for concurrent use.
+type patternSet struct {
+	nodes []patternNode
+}
+
+type patternNode struct {
+	edges  []patternEdge // Edges for bytes of the path, sorted by byte.
+	star   int32         // Node reached by a '*', or -1 if none.
+	isStar bool          // True if the node was reached by a '*'.
+
+	// Match priority of the patterns ending here, which is the length
+	// of the node's path. Anchored patterns are one byte longer.
+	priority int
+
+	// Best rules ending at this node, as indexes into the rules the
+	// set was built from, or -1 if none. Anchored rules end with '$'.
+	allow, disallow                 int
+	anchoredAllow, anchoredDisallow int
+}
+
+type patternEdge struct {
+	b  byte
+	to int32
+}
+
+// newPatternSet compiles the given rules. If normaliseIndex is true,
+// Allow patterns ending in 'index.htm' or 'index.html' also match the
+// directory itself, as per Profile.allowPriority.
+func newPatternSet(rules []Rule, normaliseIndex bool) *patternSet {
+	s := &patternSet{}
+	s.newNode(0)
+	for i, rule := range rules {
+		s.add(rule.Pattern, rule.Type, i)
+		if rule.Type == AllowRule && normaliseIndex {
+			slashPos := strings.LastIndexByte(rule.Pattern, '/')
+			if slashPos != -1 && strings.HasPrefix(rule.Pattern[slashPos:], "/index.htm") {
+				// The normalised pattern is shorter, so it can only
+				// win where the pattern itself does not match.
+				s.add(rule.Pattern[:slashPos+1]+"$", AllowRule, i)
+			}
+		}
+	}
+	// Sort the edges of each node, for binary search.
+	for i := range s.nodes {
+		edges := s.nodes[i].edges
+		sort.Slice(edges, func(a, b int) bool { return edges[a].b < edges[b].b })
+	}
+	return s
+}
+
+func (s *patternSet) newNode(priority int) int32 {
+	s.nodes = append(s.nodes, patternNode{
+		star:             -1,
+		priority:         priority,
+		allow:            -1,
+		disallow:         -1,
+		anchoredAllow:    -1,
+		anchoredDisallow: -1,
+	})
+	return int32(len(s.nodes) - 1)
+}
+
+// add adds a pattern, for the rule with the given index.
+func (s *patternSet) add(pattern string, typ RuleType, index int) {
+	anchored := strings.HasSuffix(pattern, "$")
+	body := pattern
+	if anchored {
+		body = pattern[:len(pattern)-1]
+	}
+	n := int32(0)
+	for i := 0; i < len(body); i++ {
+		n = s.child(n, body[i], i+1)
+	}
+
+	node := &s.nodes[n]
+	best := &node.disallow
+	switch {
+	case typ == AllowRule && anchored:
+		best = &node.anchoredAllow
+	case typ == AllowRule:
+		best = &node.allow
+	case anchored:
+		best = &node.anchoredDisallow
+	}
+	// Rules are added in order, so the first rule for a node wins ties.
+	if *best == -1 {
+		*best = index
+	}
+}
+
+// child returns the child of node n for byte b, adding it if needed.
+// Depth is the length of the pattern prefix that the child represents.
+func (s *patternSet) child(n int32, b byte, depth int) int32 {
+	if b == '*' {
+		if s.nodes[n].star == -1 {
+			c := s.newNode(depth)
+			s.nodes[c].isStar = true
+			s.nodes[n].star = c
+		}
+		return s.nodes[n].star
+	}
+	for _, e := range s.nodes[n].edges {
+		if e.b == b {
+			return e.to
+		}
+	}
+	c := s.newNode(depth)
+	s.nodes[n].edges = append(s.nodes[n].edges, patternEdge{b: b, to: c})
+	return c
+}
+
+// next returns the child of node n for byte b, or -1 if there is none.
+func (s *patternSet) next(n int32, b byte) int32 {
+	edges := s.nodes[n].edges
+	if len(edges) <= 8 {
+		for _, e := range edges {
+			if e.b == b {
+				return e.to
+			}
+		}
+		return -1
+	}
+	i := sort.Search(len(edges), func(i int) bool { return edges[i].b >= b })
+	if i < len(edges) && edges[i].b == b {
+		return edges[i].to
+	}
+	return -1
+}
+
+// patternScratch holds the state of a match, reused between calls.
+type patternScratch struct {
+	active, next []int32
+	seen         []uint32 // Generation at which each node was last added.
+	generation   uint32
+}
+
+var patternScratchPool = sync.Pool{
+	New: func() interface{} { return &patternScratch{} },
+}
+
+// patternResult is the best match of each rule type.
+type patternResult struct {
+	allow, disallow int // Rule indexes, or -1 for no match.
+
+	allowPriority, disallowPriority int
+}
+
+// match matches path against all patterns, and returns the best Allow and
+// Disallow rules: those with the highest priority, and then the lowest index.
+func (s *patternSet) match(path string) patternResult {
+	r := patternResult{
+		allow:            -1,
+		disallow:         -1,
+		allowPriority:    noMatchPriority,
+		disallowPriority: noMatchPriority,
+	}
+
+	sc := patternScratchPool.Get().(*patternScratch)
+	defer patternScratchPool.Put(sc)
+	if len(sc.seen) < len(s.nodes) {
+		sc.seen = make([]uint32, len(s.nodes))
+		sc.generation = 0
+	} else if uint64(sc.generation)+uint64(len(path))+2 > math.MaxUint32 {
+		// Start again, before the generation wraps around.
+		for i := range sc.seen {
+			sc.seen[i] = 0
+		}
+		sc.generation = 0
+	}
+	sc.active = sc.active[:0]
+
+	sc.generation++
+	sc.active = s.enter(sc, sc.active, 0, &r)
+	for i := 0; i < len(path) && len(sc.active) > 0; i++ {
+		sc.generation++
+		sc.next = sc.next[:0]
+		for _, n := range sc.active {
+			if c := s.next(n, path[i]); c != -1 {
+				sc.next = s.enter(sc, sc.next, c, &r)
+			}
+			if s.nodes[n].isStar {
+				// A '*' matches any byte, so its node stays active.
+				sc.next = s.enter(sc, sc.next, n, &r)
+			}
+		}
+		sc.active, sc.next = sc.next, sc.active
+	}
+	for _, n := range sc.active {
+		node := &s.nodes[n]
+		r.consider(node.anchoredAllow, node.anchoredDisallow, node.priority+1)
+	}
+	return r
+}
+
+// enter adds node n, and the nodes reached from it by '*', to the active
+// list, unless already added in this generation, and records the patterns
+// that end there.
+func (s *patternSet) enter(sc *patternScratch, active []int32, n int32, r *patternResult) []int32 {
+	for n != -1 && sc.seen[n] != sc.generation {
+		sc.seen[n] = sc.generation
+		active = append(active, n)
+		node := &s.nodes[n]
+		r.consider(node.allow, node.disallow, node.priority)
+		n = node.star
+	}
+	return active
+}
+
+// consider updates r with the given rules, which match with the given priority.
+func (r *patternResult) consider(allow, disallow, priority int) {
+	if allow != -1 && (priority > r.allowPriority || (priority == r.allowPriority && allow < r.allow)) {
+		r.allow, r.allowPriority = allow, priority
+	}
+	if disallow != -1 && (priority > r.disallowPriority || (priority == r.disallowPriority && disallow < r.disallow)) {
+		r.disallow, r.disallowPriority = disallow, priority
+	}
+}
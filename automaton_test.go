@@ -0,0 +1,176 @@
+package grobotstxt_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// ruleByRuleStrategy is LongestMatchStrategy under another type, so
+// that rules are matched one at a time, instead of being compiled.
+type ruleByRuleStrategy struct {
+	grobotstxt.LongestMatchStrategy
+}
+
+// compileRuleByRule is like CompileProfile, but rules are matched one at a time.
+func compileRuleByRule(robotstxt string, profile grobotstxt.Profile) (*grobotstxt.Robots, error) {
+	profile.MatchStrategy = ruleByRuleStrategy{}
+	return grobotstxt.CompileProfile(robotstxt, profile)
+}
+
+// randomString returns a string of up to n bytes from the given alphabet.
+func randomString(rnd *rand.Rand, alphabet string, n int) string {
+	b := make([]byte, rnd.Intn(n+1))
+	for i := range b {
+		b[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+var _ = Describe("Compiled patterns", func() {
+
+	disallowWins := grobotstxt.GoogleProfile
+	disallowWins.Name = "DisallowWins"
+	disallowWins.Rules = grobotstxt.LongestMatchDisallowWins
+
+	It("should give the same verdicts as matching rule by rule", func() {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 300; i++ {
+			var sb strings.Builder
+			for g := 0; g < 3; g++ {
+				sb.WriteString([]string{"User-agent: *\n", "User-agent: FooBot\n"}[rnd.Intn(2)])
+				for n := rnd.Intn(8); n > 0; n-- {
+					sb.WriteString([]string{"Allow: ", "Disallow: "}[rnd.Intn(2)])
+					sb.WriteString(randomString(rnd, "/ab*$", 6))
+					if rnd.Intn(8) == 0 {
+						sb.WriteString("/index.htm")
+					}
+					sb.WriteString("\n")
+				}
+			}
+			robotstxt := sb.String()
+
+			for _, profile := range []grobotstxt.Profile{grobotstxt.GoogleProfile, grobotstxt.YandexProfile, disallowWins} {
+				compiled, err := grobotstxt.CompileProfile(robotstxt, profile)
+				Expect(err).NotTo(HaveOccurred())
+				ruleByRule, err := compileRuleByRule(robotstxt, profile)
+				Expect(err).NotTo(HaveOccurred())
+
+				for j := 0; j < 20; j++ {
+					uri := "http://foo.bar/" + randomString(rnd, "/ab*$", 8)
+					for _, agent := range []string{"FooBot", "BarBot"} {
+						expected := ruleByRule.Explain([]string{agent}, uri)
+						Expect(compiled.Allowed(agent, uri)).To(Equal(expected.Allowed), "%s %q %s", profile.Name, robotstxt, uri)
+						Expect(ruleByRule.Allowed(agent, uri)).To(Equal(expected.Allowed), "%s %q %s", profile.Name, robotstxt, uri)
+						if profile.Name == "Google" {
+							Expect(grobotstxt.AgentAllowed(robotstxt, agent, uri)).To(Equal(expected.Allowed), "%q %s", robotstxt, uri)
+						}
+					}
+				}
+			}
+		}
+	})
+
+	It("should prefer longer patterns, and then earlier rules", func() {
+		const robotstxt = "User-agent: FooBot\n" +
+			"Disallow: /a*\n" +
+			"Allow: /a*\n" +
+			"Disallow: /ab\n" +
+			"Allow: /a$\n" +
+			"Disallow: /**\n" +
+			"Disallow: /b\n" +
+			"Allow: /b/index.html\n"
+		for path, expected := range map[string]bool{
+			"/a":  true,
+			"/ab": true,
+			"/ac": true,
+			"/b":  false,
+			"/b/": true,
+			"/c":  false,
+		} {
+			uri := "http://foo.bar" + path
+			Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", uri)).To(Equal(expected), path)
+			r, err := grobotstxt.Compile(robotstxt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Allowed("FooBot", uri)).To(Equal(expected), path)
+		}
+	})
+
+	It("should match merged sources", func() {
+		a, err := grobotstxt.NewSource("a", "User-agent: *\nDisallow: /a\n")
+		Expect(err).NotTo(HaveOccurred())
+		b, err := grobotstxt.NewSource("b", "User-agent: *\nDisallow: /b\n")
+		Expect(err).NotTo(HaveOccurred())
+		for _, mode := range []grobotstxt.MergeMode{grobotstxt.MostRestrictive, grobotstxt.FirstSource, grobotstxt.UnionGroups} {
+			p := grobotstxt.Merge(mode, a, b)
+			Expect(p.Allowed("FooBot", "http://foo.bar/a")).To(BeFalse())
+			Expect(p.Allowed("FooBot", "http://foo.bar/c")).To(BeTrue())
+		}
+	})
+
+})
+
+// manyRules returns a robots.txt file with n groups of rules.
+func manyRules(n int) string {
+	var sb strings.Builder
+	sb.WriteString("User-agent: *\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "Disallow: /section%d/\n", i)
+		fmt.Fprintf(&sb, "Allow: /section%d/public/\n", i)
+		fmt.Fprintf(&sb, "Disallow: /*/item%d.php$\n", i)
+		fmt.Fprintf(&sb, "Disallow: /*?session=%d\n", i)
+	}
+	return sb.String()
+}
+
+var benchmarkURIs = []string{
+	"http://example.com/",
+	"http://example.com/section17/public/page.html",
+	"http://example.com/section500/private/item3.php",
+	"http://example.com/other/deep/path/item900.php?session=4",
+}
+
+func benchmarkChecker(b *testing.B, rules int, compile func(string) (*grobotstxt.Robots, error)) {
+	r, err := compile(manyRules(rules))
+	if err != nil {
+		b.Fatal(err)
+	}
+	c := r.Checker("FooBot")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Allowed(benchmarkURIs[i%len(benchmarkURIs)])
+	}
+}
+
+func compiled(robotstxt string) (*grobotstxt.Robots, error) {
+	return grobotstxt.Compile(robotstxt)
+}
+
+func ruleByRule(robotstxt string) (*grobotstxt.Robots, error) {
+	return compileRuleByRule(robotstxt, grobotstxt.GoogleProfile)
+}
+
+func BenchmarkCheckerCompiled10(b *testing.B)     { benchmarkChecker(b, 10, compiled) }
+func BenchmarkCheckerRuleByRule10(b *testing.B)   { benchmarkChecker(b, 10, ruleByRule) }
+func BenchmarkCheckerCompiled1000(b *testing.B)   { benchmarkChecker(b, 1000, compiled) }
+func BenchmarkCheckerRuleByRule1000(b *testing.B) { benchmarkChecker(b, 1000, ruleByRule) }
+
+func BenchmarkRobotsMatcher1000(b *testing.B) {
+	robotstxt := manyRules(1000)
+	m := grobotstxt.NewRobotsMatcher()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.AgentAllowed(robotstxt, "FooBot", benchmarkURIs[i%len(benchmarkURIs)])
+	}
+}
+
+func BenchmarkMatches(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		grobotstxt.Matches("/section500/private/item3.php?session=4", "/*/item*.php$")
+	}
+}
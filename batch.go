@@ -0,0 +1,185 @@
+package grobotstxt
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// BatchResult is the verdict for one URI of a batch.
+type BatchResult struct {
+	URI     string
+	Allowed bool
+
+	// Line is the line of the rule that decided the verdict,
+	// or 0 if no rule matched, as per RobotsMatcher.MatchingLine.
+	Line int
+
+	// Err is the error from url.Parse, if the URI is invalid,
+	// in which case it is disallowed.
+	Err error
+}
+
+// AllowedBatch compiles the given robots.txt content once, and returns the
+// verdicts for the given userAgents and each of the given URIs, in order.
+// URIs are checked by the given number of worker goroutines; if workers
+// is less than 1, runtime.GOMAXPROCS(0) is used.
+//
+// It returns an error if the robots.txt content cannot be compiled,
+// as per Compile.
+func AllowedBatch(robotsBody string, userAgents []string, uris []string, workers int) ([]BatchResult, error) {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return nil, err
+	}
+	return r.Checker(userAgents...).Batch(uris, workers), nil
+}
+
+// Batch returns the verdicts for each of the given URIs, in order.
+// URIs are checked by the given number of worker goroutines; if workers
+// is less than 1, runtime.GOMAXPROCS(0) is used.
+func (c *Checker) Batch(uris []string, workers int) []BatchResult {
+	results := make([]BatchResult, len(uris))
+	workers = batchWorkers(workers)
+	if workers > len(uris) {
+		workers = len(uris)
+	}
+	if workers <= 1 {
+		for i, uri := range uris {
+			results[i] = c.check(uri)
+		}
+		return results
+	}
+
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(uris) {
+					return
+				}
+				results[i] = c.check(uris[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// BatchChan reads URIs from the given channel until it is closed, and sends
+// their verdicts to the returned channel, in the same order. The returned
+// channel is closed after the last verdict. URIs are checked by the given
+// number of worker goroutines; if workers is less than 1,
+// runtime.GOMAXPROCS(0) is used.
+//
+// The caller must receive all verdicts, or else the goroutines
+// started by BatchChan will not exit.
+func (c *Checker) BatchChan(uris <-chan string, workers int) <-chan BatchResult {
+	workers = batchWorkers(workers)
+
+	type job struct {
+		uri    string
+		result chan BatchResult
+	}
+	jobs := make(chan job)
+	// Pending results, in input order. The buffer limits
+	// how far the workers can get ahead of the receiver.
+	pending := make(chan chan BatchResult, workers)
+	out := make(chan BatchResult)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for j := range jobs {
+				j.result <- c.check(j.uri)
+			}
+		}()
+	}
+	go func() {
+		for uri := range uris {
+			j := job{uri: uri, result: make(chan BatchResult, 1)}
+			pending <- j.result
+			jobs <- j
+		}
+		close(jobs)
+		close(pending)
+	}()
+	go func() {
+		for result := range pending {
+			out <- <-result
+		}
+		close(out)
+	}()
+	return out
+}
+
+// check returns the verdict for a single URI.
+func (c *Checker) check(uri string) BatchResult {
+	path, err := parsePath(uri)
+	if err != nil {
+		return BatchResult{URI: uri, Err: err}
+	}
+	v := c.match(path)
+	return BatchResult{
+		URI:     uri,
+		Allowed: !disallowed(v.allow, v.disallow, v.everSeenSpecificAgent),
+		Line:    matchingLine(v.allow, v.disallow, v.everSeenSpecificAgent),
+	}
+}
+
+func batchWorkers(workers int) int {
+	if workers < 1 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// AgentVerdict is the verdict for one user agent, as given by AllowedByAgent.
+type AgentVerdict struct {
+	Allowed bool
+
+	// Line is the line of the rule that decided the verdict,
+	// or 0 if no rule matched, as per RobotsMatcher.MatchingLine.
+	Line int
+
+	// Specific is true if the robots.txt file has a group naming the
+	// user agent, as per RobotsMatcher.EverSeenSpecificAgent.
+	Specific bool
+}
+
+// AllowedByAgent compiles the given robots.txt content once, and returns the
+// verdict for the given URI for each of the given userAgents, separately.
+// Unlike AgentsAllowed, the user agents are not treated as a single identity.
+//
+// It returns an error if the robots.txt content cannot be compiled, as per
+// Compile, or if the URI is invalid (cannot successfully be parsed by url.Parse).
+func AllowedByAgent(robotsBody string, userAgents []string, uri string) (map[string]AgentVerdict, error) {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return nil, err
+	}
+	return r.AllowedByAgent(userAgents, uri)
+}
+
+// AllowedByAgent returns the verdict for the given URI for each of the given
+// userAgents, separately. It returns an error if the URI is invalid
+// (cannot successfully be parsed by url.Parse).
+func (r *Robots) AllowedByAgent(userAgents []string, uri string) (map[string]AgentVerdict, error) {
+	path, err := parsePath(uri)
+	if err != nil {
+		return nil, err
+	}
+	verdicts := make(map[string]AgentVerdict, len(userAgents))
+	for _, agent := range userAgents {
+		v := r.Checker(agent).match(path)
+		verdicts[agent] = AgentVerdict{
+			Allowed:  !disallowed(v.allow, v.disallow, v.everSeenSpecificAgent),
+			Line:     matchingLine(v.allow, v.disallow, v.everSeenSpecificAgent),
+			Specific: v.everSeenSpecificAgent,
+		}
+	}
+	return verdicts, nil
+}
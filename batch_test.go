@@ -0,0 +1,165 @@
+package grobotstxt_test
+
+import (
+	"fmt"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch", func() {
+
+	const robotstxt = "User-agent: *\n" +
+		"Disallow: /private/\n" +
+		"Allow: /private/public/\n" +
+		"\n" +
+		"User-agent: FooBot\n" +
+		"Disallow: /foo/\n"
+
+	// uris returns n URIs, with a mix of verdicts.
+	uris := func(n int) []string {
+		var uris []string
+		for i := 0; i < n; i++ {
+			switch i % 4 {
+			case 0:
+				uris = append(uris, fmt.Sprintf("http://foo.bar/private/%d", i))
+			case 1:
+				uris = append(uris, fmt.Sprintf("http://foo.bar/private/public/%d", i))
+			case 2:
+				uris = append(uris, fmt.Sprintf("http://foo.bar/foo/%d", i))
+			default:
+				uris = append(uris, fmt.Sprintf("http://foo.bar/%d", i))
+			}
+		}
+		return uris
+	}
+
+	// expected returns the results given by a RobotsMatcher.
+	expected := func(agent string, uris []string) []grobotstxt.BatchResult {
+		var results []grobotstxt.BatchResult
+		m := grobotstxt.NewRobotsMatcher()
+		for _, uri := range uris {
+			allowed := m.AgentAllowed(robotstxt, agent, uri)
+			results = append(results, grobotstxt.BatchResult{
+				URI:     uri,
+				Allowed: allowed,
+				Line:    m.MatchingLine(),
+				Err:     m.Err(),
+			})
+		}
+		return results
+	}
+
+	It("should give the same verdicts and lines as RobotsMatcher", func() {
+		uris := uris(100)
+		for _, agent := range []string{"FooBot", "BarBot"} {
+			want := expected(agent, uris)
+			for _, workers := range []int{0, 1, 3, 200} {
+				results, err := grobotstxt.AllowedBatch(robotstxt, []string{agent}, uris, workers)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(Equal(want))
+			}
+		}
+		Expect(expected("BarBot", uris[:2])).To(Equal([]grobotstxt.BatchResult{
+			{URI: "http://foo.bar/private/0", Allowed: false, Line: 2},
+			{URI: "http://foo.bar/private/public/1", Allowed: true, Line: 3},
+		}))
+	})
+
+	It("should report invalid URIs", func() {
+		c, err := grobotstxt.NewChecker(robotstxt, "BarBot")
+		Expect(err).NotTo(HaveOccurred())
+		results := c.Batch([]string{"http://foo.bar/private/", "http://[::1", "http://foo.bar/"}, 2)
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Allowed).To(BeFalse())
+		Expect(results[1].Allowed).To(BeFalse())
+		Expect(results[1].Err).To(HaveOccurred())
+		Expect(results[2].Allowed).To(BeTrue())
+		Expect(results[2].Err).NotTo(HaveOccurred())
+	})
+
+	It("should handle empty batches", func() {
+		c, err := grobotstxt.NewChecker(robotstxt, "BarBot")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Batch(nil, 4)).To(BeEmpty())
+
+		in := make(chan string)
+		close(in)
+		Eventually(c.BatchChan(in, 4)).Should(BeClosed())
+	})
+
+	It("should stream verdicts in order", func() {
+		uris := uris(500)
+		c, err := grobotstxt.NewChecker(robotstxt, "FooBot")
+		Expect(err).NotTo(HaveOccurred())
+		for _, workers := range []int{0, 1, 8} {
+			in := make(chan string)
+			go func() {
+				for _, uri := range uris {
+					in <- uri
+				}
+				close(in)
+			}()
+			var results []grobotstxt.BatchResult
+			for r := range c.BatchChan(in, workers) {
+				results = append(results, r)
+			}
+			Expect(results).To(Equal(expected("FooBot", uris)))
+		}
+	})
+
+})
+
+var _ = Describe("AllowedByAgent", func() {
+
+	const robotstxt = "User-agent: *\n" +
+		"Disallow: /private/\n" +
+		"\n" +
+		"User-agent: FooBot\n" +
+		"Allow: /private/foo/\n" +
+		"\n" +
+		"User-agent: BarBot\n" +
+		"Disallow: /\n" +
+		"\n" +
+		"User-agent: QuxBot\n" +
+		"Disallow:\n"
+
+	agents := []string{"FooBot", "BarBot", "BazBot", "QuxBot", "FooBot/1.2"}
+
+	It("should give each agent its own verdict", func() {
+		verdicts, err := grobotstxt.AllowedByAgent(robotstxt, agents, "http://foo.bar/private/foo/x")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verdicts).To(Equal(map[string]grobotstxt.AgentVerdict{
+			"FooBot":     {Allowed: true, Line: 5, Specific: true},
+			"BarBot":     {Allowed: false, Line: 8, Specific: true},
+			"BazBot":     {Allowed: false, Line: 2, Specific: false},
+			"QuxBot":     {Allowed: true, Line: 11, Specific: true},
+			"FooBot/1.2": {Allowed: false, Line: 2, Specific: false},
+		}))
+	})
+
+	It("should agree with RobotsMatcher", func() {
+		m := grobotstxt.NewRobotsMatcher()
+		for _, path := range []string{"/", "/private/", "/private/foo/", "/other"} {
+			uri := "http://foo.bar" + path
+			verdicts, err := grobotstxt.AllowedByAgent(robotstxt, agents, uri)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verdicts).To(HaveLen(len(agents)))
+			for _, agent := range agents {
+				allowed := m.AgentAllowed(robotstxt, agent, uri)
+				Expect(verdicts[agent]).To(Equal(grobotstxt.AgentVerdict{
+					Allowed:  allowed,
+					Line:     m.MatchingLine(),
+					Specific: m.EverSeenSpecificAgent(),
+				}), agent+" "+path)
+			}
+		}
+	})
+
+	It("should report invalid URIs", func() {
+		_, err := grobotstxt.AllowedByAgent(robotstxt, agents, "http://[::1")
+		Expect(err).To(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,199 @@
+package grobotstxt
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Builder builds the content of a robots.txt file.
+//
+// Patterns are escaped in the same way as by the parser, and user agents
+// are validated, so that the content parses back into the groups, rules
+// and sitemaps that were added. The first invalid value is reported by
+// WriteTo and Err.
+//
+// For example:
+//
+//	b := NewBuilder()
+//	b.Group("FooBot").Disallow("/private/")
+//	b.Group("*").Allow("/").CrawlDelay(time.Second)
+//	b.Sitemap("https://example.com/sitemap.xml")
+//	b.WriteTo(w)
+type Builder struct {
+	comments []string
+	groups   []*GroupBuilder
+	sitemaps []string
+
+	err error // First invalid value added.
+}
+
+// NewBuilder creates an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Comment adds a comment to the start of the file. Multi-line text is
+// written as several comment lines.
+func (b *Builder) Comment(text string) *Builder {
+	b.comments = append(b.comments, text)
+	return b
+}
+
+// Group adds a user-agent group for the given agents, which must each be "*",
+// or a valid product token, such as "FooBot".
+func (b *Builder) Group(agents ...string) *GroupBuilder {
+	g := &GroupBuilder{builder: b, agents: agents}
+	b.groups = append(b.groups, g)
+	if len(agents) == 0 {
+		b.fail(fmt.Errorf("grobotstxt: group has no user-agents"))
+	}
+	for _, agent := range agents {
+		if agent != "*" && !isValidUserAgentToObey(agent) {
+			b.fail(fmt.Errorf("grobotstxt: invalid user-agent %q", agent))
+		}
+	}
+	return g
+}
+
+// Sitemap adds a "Sitemap:" line, which is written at the end of the file.
+func (b *Builder) Sitemap(url string) *Builder {
+	b.sitemaps = append(b.sitemaps, b.checkValue("sitemap", url))
+	return b
+}
+
+// Err returns the first error in the values added to the Builder, or nil.
+func (b *Builder) Err() error {
+	return b.err
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// checkValue records an error if the given value cannot be written on a
+// single robots.txt line, without being cut short by a comment.
+func (b *Builder) checkValue(what, value string) string {
+	if strings.ContainsAny(value, "#\r\n") {
+		b.fail(fmt.Errorf("grobotstxt: invalid %s %q", what, value))
+	}
+	return value
+}
+
+// String returns the content of the robots.txt file, or an empty string
+// if the Builder has an error.
+func (b *Builder) String() string {
+	var sb strings.Builder
+	if _, err := b.WriteTo(&sb); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// WriteTo writes the content of the robots.txt file to w. It writes
+// nothing, and returns the error, if any value added was invalid.
+//
+// A group without any Allow or Disallow rules is written with an empty
+// "Disallow:" rule, which allows everything, so that it is not merged
+// with the following group by the parser.
+func (b *Builder) WriteTo(w io.Writer) (int64, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	var lines []string
+	for _, c := range b.comments {
+		lines = appendComment(lines, c)
+	}
+	for _, g := range b.groups {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		for _, agent := range g.agents {
+			lines = append(lines, "User-agent: "+agent)
+		}
+		hasRule := false
+		for _, l := range g.lines {
+			if l.key == "" {
+				lines = appendComment(lines, l.value)
+				continue
+			}
+			hasRule = hasRule || l.key == "Allow" || l.key == "Disallow"
+			lines = append(lines, strings.TrimSpace(l.key+": "+l.value))
+		}
+		if !hasRule {
+			lines = append(lines, "Disallow:")
+		}
+	}
+	if len(b.sitemaps) > 0 && len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	for _, s := range b.sitemaps {
+		lines = append(lines, "Sitemap: "+s)
+	}
+
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(l)
+		sb.WriteByte('\n')
+	}
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+func appendComment(lines []string, text string) []string {
+	for _, c := range strings.Split(text, "\n") {
+		lines = append(lines, strings.TrimSpace("# "+strings.TrimRight(c, "\r")))
+	}
+	return lines
+}
+
+// GroupBuilder builds a user-agent group. See Builder.Group.
+type GroupBuilder struct {
+	builder *Builder
+	agents  []string
+	lines   []builderLine
+}
+
+// builderLine is a line of a group. Lines without a key are comments.
+type builderLine struct {
+	key   string
+	value string
+}
+
+// Comment adds a comment to the group.
+func (g *GroupBuilder) Comment(text string) *GroupBuilder {
+	g.lines = append(g.lines, builderLine{value: text})
+	return g
+}
+
+// Allow adds an "Allow:" rule to the group. The pattern is escaped as it
+// would be by the parser.
+func (g *GroupBuilder) Allow(pattern string) *GroupBuilder {
+	return g.rule("Allow", pattern)
+}
+
+// Disallow adds a "Disallow:" rule to the group. The pattern is escaped as
+// it would be by the parser. An empty pattern allows everything.
+func (g *GroupBuilder) Disallow(pattern string) *GroupBuilder {
+	return g.rule("Disallow", pattern)
+}
+
+func (g *GroupBuilder) rule(key, pattern string) *GroupBuilder {
+	pattern = g.builder.checkValue("pattern", strings.TrimSpace(pattern))
+	g.lines = append(g.lines, builderLine{key, escapePattern(pattern)})
+	return g
+}
+
+// CrawlDelay adds a "Crawl-delay:" line to the group. The delay is written
+// in seconds, and must not be negative.
+func (g *GroupBuilder) CrawlDelay(d time.Duration) *GroupBuilder {
+	if d < 0 {
+		g.builder.fail(fmt.Errorf("grobotstxt: invalid crawl-delay %v", d))
+	}
+	g.lines = append(g.lines, builderLine{"Crawl-delay", strconv.FormatFloat(d.Seconds(), 'f', -1, 64)})
+	return g
+}
@@ -0,0 +1,105 @@
+package grobotstxt_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Builder", func() {
+
+	build := func() *grobotstxt.Builder {
+		b := grobotstxt.NewBuilder().Comment("Generated.\nDo not edit.")
+		b.Group("FooBot", "BarBot").
+			Comment("No private pages.").
+			Disallow("/private/").
+			Allow("/private/säle").
+			CrawlDelay(1500 * time.Millisecond)
+		b.Group("BazBot")
+		b.Group("*").Disallow("/tmp/")
+		b.Sitemap("https://example.com/sitemap.xml")
+		return b
+	}
+
+	It("should write robots.txt content", func() {
+		Expect(build().String()).To(Equal("# Generated.\n" +
+			"# Do not edit.\n" +
+			"\n" +
+			"User-agent: FooBot\n" +
+			"User-agent: BarBot\n" +
+			"# No private pages.\n" +
+			"Disallow: /private/\n" +
+			"Allow: /private/s%C3%A4le\n" +
+			"Crawl-delay: 1.5\n" +
+			"\n" +
+			"User-agent: BazBot\n" +
+			"Disallow:\n" +
+			"\n" +
+			"User-agent: *\n" +
+			"Disallow: /tmp/\n" +
+			"\n" +
+			"Sitemap: https://example.com/sitemap.xml\n"))
+	})
+
+	It("should round-trip through the parser", func() {
+		var sb strings.Builder
+		_, err := build().WriteTo(&sb)
+		Expect(err).NotTo(HaveOccurred())
+		robotstxt := sb.String()
+		Expect(grobotstxt.Format(robotstxt)).To(Equal(robotstxt))
+
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Groups()).To(Equal([]grobotstxt.Group{
+			{
+				Agents: []string{"FooBot", "BarBot"},
+				Rules: []grobotstxt.Rule{
+					{Type: grobotstxt.DisallowRule, Pattern: "/private/", Line: 7},
+					{Type: grobotstxt.AllowRule, Pattern: "/private/s%C3%A4le", Line: 8},
+				},
+				CrawlDelay:    1500 * time.Millisecond,
+				HasCrawlDelay: true,
+			},
+			{
+				Agents: []string{"BazBot"},
+				Rules:  []grobotstxt.Rule{{Type: grobotstxt.DisallowRule, Pattern: "", Line: 12}},
+			},
+			{
+				Agents: []string{"*"},
+				Rules:  []grobotstxt.Rule{{Type: grobotstxt.DisallowRule, Pattern: "/tmp/", Line: 15}},
+				Global: true,
+			},
+		}))
+		Expect(robots.Sitemaps()).To(Equal([]string{"https://example.com/sitemap.xml"}))
+		Expect(robots.Allowed("BazBot", "https://example.com/tmp/")).To(BeTrue())
+	})
+
+	It("should reject invalid values", func() {
+		// build returns a Builder with a group made by the given func.
+		build := func(group func(b *grobotstxt.Builder)) *grobotstxt.Builder {
+			b := grobotstxt.NewBuilder()
+			group(b)
+			return b
+		}
+		for _, b := range []*grobotstxt.Builder{
+			build(func(b *grobotstxt.Builder) { b.Group("Foo Bot") }),
+			build(func(b *grobotstxt.Builder) { b.Group("FooBot/2.1") }),
+			build(func(b *grobotstxt.Builder) { b.Group() }),
+			build(func(b *grobotstxt.Builder) { b.Group("*").Disallow("/a#b") }),
+			build(func(b *grobotstxt.Builder) { b.Group("*").Allow("/a\nb") }),
+			build(func(b *grobotstxt.Builder) { b.Group("*").CrawlDelay(-time.Second) }),
+			grobotstxt.NewBuilder().Sitemap("http://x/#y"),
+		} {
+			Expect(b.Err()).To(HaveOccurred())
+			var sb strings.Builder
+			n, err := b.WriteTo(&sb)
+			Expect(err).To(Equal(b.Err()))
+			Expect(n).To(BeZero())
+			Expect(b.String()).To(BeEmpty())
+		}
+	})
+
+})
@@ -0,0 +1,86 @@
+package grobotstxt_test
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checker", func() {
+
+	const robotstxt = "User-agent: *\n" +
+		"Disallow: /x/\n" +
+		"User-agent: FooBot\n" +
+		"Disallow: /\n" +
+		"Allow: /fish\n" +
+		"Allow: /allowed-slash/index.html\n"
+
+	uris := []string{
+		"http://foo.bar/",
+		"http://foo.bar/x/page",
+		"http://foo.bar/fish",
+		"http://foo.bar/fish/salmon.html",
+		"http://foo.bar/catfish",
+		"http://foo.bar/allowed-slash/",
+		"http://foo.bar/allowed-slash/index.htm",
+		"http://foo.bar/%zz",
+	}
+
+	It("should agree with RobotsMatcher", func() {
+		for _, agent := range []string{"FooBot", "BarBot"} {
+			checker, err := grobotstxt.NewChecker(robotstxt, agent)
+			Expect(err).NotTo(HaveOccurred())
+			for _, uri := range uris {
+				expected := grobotstxt.AgentAllowed(robotstxt, agent, uri)
+				Expect(checker.Allowed(uri)).To(Equal(expected), agent+" "+uri)
+			}
+		}
+	})
+
+	It("should report specific agents", func() {
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Checker("FooBot").EverSeenSpecificAgent()).To(BeTrue())
+		Expect(robots.Checker("BarBot").EverSeenSpecificAgent()).To(BeFalse())
+		Expect(robots.Checker("BarBot", "foobot").EverSeenSpecificAgent()).To(BeTrue())
+	})
+
+	// Run with -race to verify that a shared Checker and Robots
+	// need no locking.
+	It("should be safe for concurrent use", func() {
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		checker := robots.Checker("FooBot")
+
+		expected := make([]bool, len(uris))
+		for i, uri := range uris {
+			expected[i] = grobotstxt.AgentAllowed(robotstxt, "FooBot", uri)
+		}
+
+		const workers = 32
+		var failures int32
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for n := 0; n < 50; n++ {
+					for i, uri := range uris {
+						if checker.Allowed(uri) != expected[i] {
+							atomic.AddInt32(&failures, 1)
+						}
+						if robots.Allowed("FooBot", uri) != expected[i] {
+							atomic.AddInt32(&failures, 1)
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		Expect(failures).To(BeZero())
+	})
+
+})
@@ -0,0 +1,99 @@
+// Command robotsdiff reports how crawl behaviour changes between two
+// versions of a robots.txt file.
+//
+// Usage:
+//
+//	robotsdiff [flags] <old robots.txt> <new robots.txt>
+//
+// Flags:
+//
+//	-u  file of sample URIs, one per line, to check for changed verdicts
+//
+// Rules, sitemaps and crawl delays are compared for every user agent named
+// in either file. Exits with status code 0 if there are no changes, 1 if
+// there are changes, or 2 otherwise (e.g. bad inputs).
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/jimsmart/grobotstxt"
+)
+
+var urisFile = flag.String("u", "", "file of sample URIs, one per line, to check for changed verdicts")
+
+func usage() {
+	fmt.Fprint(os.Stderr, "Shows how crawl behaviour changes between two robots.txt files.\n\n")
+	fmt.Fprint(os.Stderr, "Usage:\n"+
+		"  "+os.Args[0]+" [flags] <old robots.txt> <new robots.txt>\n\n")
+	flag.PrintDefaults()
+}
+
+func loadFile(filename string) (string, error) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func loadURIs(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var uris []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if uri := strings.TrimSpace(s.Text()); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris, s.Err()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprint(os.Stderr, "Invalid amount of arguments. Showing help.\n\n")
+		usage()
+		os.Exit(2)
+	}
+
+	var bodies []string
+	for _, filename := range flag.Args() {
+		body, err := loadFile(filename)
+		if err != nil {
+			fmt.Fprint(os.Stderr, "failed to read file \""+filename+"\"\n")
+			os.Exit(2)
+		}
+		bodies = append(bodies, body)
+	}
+
+	var uris []string
+	if *urisFile != "" {
+		var err error
+		uris, err = loadURIs(*urisFile)
+		if err != nil {
+			fmt.Fprint(os.Stderr, "failed to read file \""+*urisFile+"\"\n")
+			os.Exit(2)
+		}
+	}
+
+	d, err := grobotstxt.Diff(bodies[0], bodies[1], uris)
+	if err != nil {
+		fmt.Fprint(os.Stderr, err.Error()+"\n")
+		os.Exit(2)
+	}
+	fmt.Fprint(os.Stdout, d.String())
+	if !d.Empty() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,98 @@
+// Command robotsfmt formats robots.txt files into canonical form.
+//
+// Without an explicit path, it processes the standard input. Given a file,
+// it operates on that file. By default, robotsfmt prints the formatted
+// content to standard output.
+//
+// Usage:
+//
+//	robotsfmt [flags] [path ...]
+//
+// Flags:
+//
+//	-l  list files whose formatting differs from robotsfmt's
+//	-w  write result to (source) file instead of stdout
+//
+// Formatting never changes which URIs are allowed for which user agents.
+// See grobotstxt.Format for details of the canonical form.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/jimsmart/grobotstxt"
+)
+
+var (
+	list  = flag.Bool("l", false, "list files whose formatting differs from robotsfmt's")
+	write = flag.Bool("w", false, "write result to (source) file instead of stdout")
+)
+
+func usage() {
+	fmt.Fprint(os.Stderr, "Formats robots.txt files into canonical form.\n\n")
+	fmt.Fprint(os.Stderr, "Usage:\n"+
+		"  "+os.Args[0]+" [flags] [path ...]\n\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprint(os.Stderr, "error: cannot use -w with standard input\n")
+			os.Exit(2)
+		}
+		if err := processFile("<standard input>", os.Stdin); err != nil {
+			fmt.Fprint(os.Stderr, err.Error()+"\n")
+			os.Exit(2)
+		}
+		return
+	}
+
+	exitCode := 0
+	for _, filename := range flag.Args() {
+		f, err := os.Open(filename)
+		if err == nil {
+			err = processFile(filename, f)
+			f.Close()
+		}
+		if err != nil {
+			fmt.Fprint(os.Stderr, err.Error()+"\n")
+			exitCode = 2
+		}
+	}
+	os.Exit(exitCode)
+}
+
+func processFile(filename string, f *os.File) error {
+	src, err := ioutil.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	res := []byte(grobotstxt.Format(string(src)))
+
+	if *list || *write {
+		if bytes.Equal(src, res) {
+			return nil
+		}
+		if *list {
+			fmt.Fprintln(os.Stdout, filename)
+		}
+		if *write {
+			fi, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(filename, res, fi.Mode().Perm())
+		}
+		return nil
+	}
+	_, err = os.Stdout.Write(res)
+	return err
+}
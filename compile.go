@@ -0,0 +1,580 @@
+package grobotstxt
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// RuleType denotes the type of a rule within a user-agent group.
+type RuleType int
+
+// Rule types.
+const (
+	AllowRule    RuleType = iota // AllowRule for "Allow:" lines.
+	DisallowRule                 // DisallowRule for "Disallow:" lines.
+)
+
+// String returns the robots.txt key for the rule type.
+func (t RuleType) String() string {
+	if t == AllowRule {
+		return "Allow"
+	}
+	return "Disallow"
+}
+
+// Rule is a single Allow or Disallow line of a robots.txt file.
+type Rule struct {
+	Type    RuleType
+	Pattern string // Pattern, as escaped by the parser.
+	Line    int    // Line number of the rule within the robots.txt file.
+
+	original string // Pattern as written, if escaping changed it.
+}
+
+// Original returns the rule's pattern as written in the robots.txt file,
+// before it was escaped by the parser.
+func (r Rule) Original() string {
+	if r.original != "" {
+		return r.original
+	}
+	return r.Pattern
+}
+
+// Group is a user-agent group of a robots.txt file: one or more
+// User-agent lines, followed by the rules that apply to those agents.
+type Group struct {
+	// Agents holds the product tokens of the group's User-agent lines,
+	// as extracted by the matcher. The global agent is always "*".
+	Agents []string
+	Rules  []Rule
+
+	// Global is true if any of the group's User-agent lines names the
+	// global agent. Values such as "*/1.0" have the token "*", but
+	// are not the global agent.
+	Global bool
+
+	// CrawlDelay is the group's first valid Crawl-delay,
+	// if HasCrawlDelay is true.
+	CrawlDelay    time.Duration
+	HasCrawlDelay bool
+
+	RequestRates []RequestRate // All valid Request-rate values of the group.
+	VisitTimes   []VisitTime   // All valid Visit-time values of the group.
+
+	// UsageRules holds all valid Content-Signal and Content-Usage
+	// values of the group.
+	UsageRules []UsageRule
+
+	source string // Name of the Source the group came from, if merged.
+
+	starToken bool // True if a value that is not the global agent has the token "*".
+}
+
+// isGlobal returns true if the group applies to the global agent '*'.
+func (g *Group) isGlobal() bool {
+	return g.Global
+}
+
+// isToken returns true if the given agent of the group is a product token,
+// rather than the global agent.
+func (g *Group) isToken(agent string) bool {
+	return agent != "*" || g.starToken
+}
+
+// isSpecific returns true if the group names any of the given userAgents.
+func (g *Group) isSpecific(userAgents []string) bool {
+	for _, agent := range g.Agents {
+		if !g.isToken(agent) {
+			continue
+		}
+		for _, userAgent := range userAgents {
+			if equalsIgnoreCase(agent, userAgent) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Robots is a compiled, immutable representation of a robots.txt file.
+//
+// A robots.txt body can be compiled once, and then matched against
+// many URIs and user agents, without being parsed again. Robots gives
+// exactly the same verdicts as RobotsMatcher, and is safe for
+// concurrent use by multiple goroutines. A Robots compiled by
+// CompileProfile may instead interpret the file as another crawler does.
+//
+// When matching many URIs for the same user agents, use a Checker.
+type Robots struct {
+	groups   []Group
+	sitemaps []string
+
+	hosts       []string     // Yandex Host values.
+	cleanParams []CleanParam // Yandex Clean-param values.
+
+	truncated int // Line at which the body was cut short, or 0.
+
+	profile Profile
+
+	patterns map[*Group]*patternSet // Compiled rules of each group, if any.
+}
+
+// Compile parses the given robots.txt content into a Robots.
+// It returns an error if the parser reports one, as per Parser.Err.
+//
+// Compile follows Google's parser, and has no strict mode: for strict
+// RFC 9309 verdicts, use a RobotsMatcher with Strict set.
+func Compile(robotsBody string) (*Robots, error) {
+	return compile(NewParser(robotsBody, nil), GoogleProfile)
+}
+
+// CompileBytes is like Compile, but takes the robots.txt content as a byte slice.
+// As with ParseBytes, each line that is parsed is copied, but not the whole body.
+func CompileBytes(robotsBody []byte) (*Robots, error) {
+	return compile(newParser(bytesBody(robotsBody), nil, optionsOf(nil)), GoogleProfile)
+}
+
+// CompileReader is like Compile, but streams the robots.txt content from
+// the given reader. It also returns any error from the reader, other than io.EOF.
+func CompileReader(r io.Reader) (*Robots, error) {
+	return compile(newReaderParser(byteReader(r), nil, optionsOf(nil)), GoogleProfile)
+}
+
+func compile(p *Parser, profile Profile) (*Robots, error) {
+	if profile.MatchStrategy == nil {
+		profile.MatchStrategy = LongestMatchStrategy{}
+	}
+	c := &compiler{}
+	p.handler = c
+	p.onValue = func(lineNum int, key parsedKey, value string) {
+		c.rawValue = value
+	}
+	p.Parse()
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	c.robots.truncated, _ = p.Truncated()
+	c.robots.profile = profile
+	c.robots.compilePatterns()
+	return c.robots, nil
+}
+
+// compilePatterns compiles the rules of each group into a patternSet, so that
+// all the rules of a group can be matched at once, if the profile allows it.
+func (r *Robots) compilePatterns() {
+	r.patterns = nil
+	_, longest := r.profile.MatchStrategy.(LongestMatchStrategy)
+	if !longest || r.profile.Rules == FirstMatch {
+		return
+	}
+	r.patterns = make(map[*Group]*patternSet, len(r.groups))
+	for i := range r.groups {
+		g := &r.groups[i]
+		r.patterns[g] = newPatternSet(g.Rules, r.profile.NormaliseIndex)
+	}
+}
+
+// Truncated reports whether the robots.txt body was larger than
+// DefaultMaxBytes, and if so, the number of the line at which it was cut.
+// Content after the cut is ignored.
+func (r *Robots) Truncated() (lineNum int, ok bool) {
+	return r.truncated, r.truncated != 0
+}
+
+// Groups returns the user-agent groups of the robots.txt file,
+// in the order they appear. The result must not be modified.
+func (r *Robots) Groups() []Group {
+	return r.groups
+}
+
+// Sitemaps returns all "Sitemap:" values of the robots.txt file.
+func (r *Robots) Sitemaps() []string {
+	return r.sitemaps
+}
+
+// AgentsAllowed returns true if the given URI is allowed to be
+// fetched by any user agent in the list.
+//
+// AgentsAllowed will also return false if the given URI is invalid
+// (cannot successfully be parsed by url.Parse).
+func (r *Robots) AgentsAllowed(userAgents []string, uri string) bool {
+	return r.Checker(userAgents...).Allowed(uri)
+}
+
+// Allowed returns true if the given URI is allowed to be fetched
+// by the given user agent.
+//
+// Allowed will also return false if the given URI is invalid
+// (cannot successfully be parsed by url.Parse).
+func (r *Robots) Allowed(userAgent string, uri string) bool {
+	return r.AgentsAllowed([]string{userAgent}, uri)
+}
+
+// Checker returns a Checker for the given user agents, which are
+// treated as a single identity, as per AgentsAllowed.
+func (r *Robots) Checker(userAgents ...string) *Checker {
+	c := &Checker{
+		profile:  r.profile,
+		patterns: r.patterns,
+	}
+	specific, global := r.selectGroups(userAgents)
+	for _, g := range specific {
+		c.specific = append(c.specific, g.Rules...)
+	}
+	for _, g := range global {
+		c.global = append(c.global, g.Rules...)
+	}
+	c.specificGroups, c.globalGroups = specific, global
+	c.everSeenSpecificAgent = len(specific) > 0
+
+	// Other group directives are taken from the same groups
+	// as the rules that decide the verdict.
+	groups := global
+	if c.everSeenSpecificAgent {
+		groups = specific
+	}
+	for _, g := range groups {
+		c.directives.add(g)
+	}
+	return c
+}
+
+// groupDirectives holds the values of the non-rule directives
+// (such as Crawl-delay) that apply to a Checker's user agents.
+type groupDirectives struct {
+	crawlDelay    time.Duration
+	hasCrawlDelay bool
+
+	requestRates []RequestRate
+	visitTimes   []VisitTime
+	usageRules   []UsageRule
+}
+
+// add merges the directives of the given group into d.
+// For single valued directives, the first value found wins.
+func (d *groupDirectives) add(g *Group) {
+	if g.HasCrawlDelay && !d.hasCrawlDelay {
+		d.crawlDelay = g.CrawlDelay
+		d.hasCrawlDelay = true
+	}
+	d.requestRates = append(d.requestRates, g.RequestRates...)
+	d.visitTimes = append(d.visitTimes, g.VisitTimes...)
+	d.usageRules = append(d.usageRules, g.UsageRules...)
+}
+
+// Checker matches URIs against the rules of a Robots that apply to a
+// fixed set of user agents. The groups for the user agents are selected
+// once, when the Checker is created.
+//
+// A Checker is immutable, and is safe for concurrent use by multiple
+// goroutines.
+type Checker struct {
+	specific []Rule // Rules from groups naming one of the user agents.
+	global   []Rule // Rules from global groups.
+
+	specificGroups, globalGroups []*Group // Groups of the above rules.
+
+	patterns map[*Group]*patternSet // Compiled rules of each group, if any.
+
+	everSeenSpecificAgent bool // True if any group named one of the user agents.
+
+	directives groupDirectives // Directives from the groups that apply.
+
+	profile Profile
+}
+
+// NewChecker compiles the given robots.txt content, and returns a
+// Checker for the given user agents.
+func NewChecker(robotsBody string, userAgents ...string) (*Checker, error) {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return nil, err
+	}
+	return r.Checker(userAgents...), nil
+}
+
+// Allowed returns true if the given URI is allowed to be fetched
+// by the Checker's user agents.
+//
+// Allowed will also return false if the given URI is invalid
+// (cannot successfully be parsed by url.Parse).
+func (c *Checker) Allowed(uri string) bool {
+	path, ok := normalisedPath(uri)
+	if !ok {
+		return false
+	}
+	v := c.match(path)
+	return !disallowed(v.allow, v.disallow, v.everSeenSpecificAgent)
+}
+
+// EverSeenSpecificAgent returns true iff the robots.txt file referred
+// explicitly to one of the Checker's user agents.
+func (c *Checker) EverSeenSpecificAgent() bool {
+	return c.everSeenSpecificAgent
+}
+
+// CrawlDelay returns the crawl delay for the Checker's user agents.
+//
+// The groups are chosen in the same way as for Allow and Disallow:
+// if the robots.txt file has a group for one of the user agents, only
+// those groups are used, otherwise the global '*' groups are used.
+// Specific reports which of the two applied, and ok is false if the
+// groups have no valid Crawl-delay.
+func (c *Checker) CrawlDelay() (delay time.Duration, specific bool, ok bool) {
+	return c.directives.crawlDelay, c.everSeenSpecificAgent, c.directives.hasCrawlDelay
+}
+
+// RequestRates returns the request rates for the Checker's user agents,
+// taken from the same groups as CrawlDelay.
+func (c *Checker) RequestRates() []RequestRate {
+	return c.directives.requestRates
+}
+
+// VisitTimes returns the visit times for the Checker's user agents,
+// taken from the same groups as CrawlDelay.
+func (c *Checker) VisitTimes() []VisitTime {
+	return c.directives.visitTimes
+}
+
+// VisitAllowed returns true if the Checker's user agents may visit at the
+// given time: that is, if no visit times apply, or if any of them contains t.
+func (c *Checker) VisitAllowed(t time.Time) bool {
+	if len(c.directives.visitTimes) == 0 {
+		return true
+	}
+	for _, v := range c.directives.visitTimes {
+		if v.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// verdict holds the outcome of matching a path against the rules of a Checker.
+type verdict struct {
+	allow    *matchHierarchy
+	disallow *matchHierarchy
+
+	everSeenSpecificAgent bool
+}
+
+// match matches the given path against the Checker's rules, in the same way
+// that RobotsMatcher does during parsing. All state is local to the call.
+func (c *Checker) match(path string) *verdict {
+	v := &verdict{
+		allow:                 newMatchHierarchy(),
+		disallow:              newMatchHierarchy(),
+		everSeenSpecificAgent: c.everSeenSpecificAgent,
+	}
+	c.matchGroups(c.specificGroups, c.specific, path, v.allow.specific, v.disallow.specific)
+	c.matchGroups(c.globalGroups, c.global, path, v.allow.global, v.disallow.global)
+	return v
+}
+
+// matchGroups matches the given path against the rules of the given groups,
+// using their compiled patterns if they have them, or else matchRules.
+func (c *Checker) matchGroups(groups []*Group, rules []Rule, path string, allow, disallow *match) {
+	if c.patterns == nil {
+		c.matchRules(rules, path, allow, disallow)
+		return
+	}
+	for _, g := range groups {
+		// Earlier groups win ties, as earlier rules do in matchRules.
+		r := c.patterns[g].match(path)
+		if r.allow != -1 && allow.priority < r.allowPriority {
+			allow.Set(r.allowPriority, g.Rules[r.allow].Line)
+		}
+		if r.disallow != -1 && disallow.priority < r.disallowPriority {
+			disallow.Set(r.disallowPriority, g.Rules[r.disallow].Line)
+		}
+	}
+	c.profile.resolve(allow, disallow)
+}
+
+func (c *Checker) matchRules(rules []Rule, path string, allow, disallow *match) {
+	for _, rule := range rules {
+		best := disallow
+		var priority int
+		if rule.Type == AllowRule {
+			best = allow
+			priority, _ = c.profile.allowPriority(path, rule.Pattern)
+		} else {
+			priority = c.profile.MatchStrategy.MatchDisallow(path, rule.Pattern)
+		}
+		if c.profile.Rules == FirstMatch {
+			// Only the first non-empty match counts.
+			if priority > 0 && best.priority <= 0 {
+				best.Set(priority, rule.Line)
+			}
+		} else if best.priority < priority {
+			best.Set(priority, rule.Line)
+		}
+	}
+	c.profile.resolve(allow, disallow)
+}
+
+// allowPriority returns the match priority of an Allow pattern, applying
+// the same 'index.html' normalisation as RobotsMatcher.HandleAllow, if
+// the profile uses it. It also returns the normalised pattern, if the
+// normalisation was used and matched, or an empty string otherwise.
+func (p *Profile) allowPriority(path, pattern string) (int, string) {
+	s := p.MatchStrategy
+	priority := s.MatchAllow(path, pattern)
+	if priority < 0 && p.NormaliseIndex {
+		// Google-specific optimization: 'index.htm' and 'index.html' are normalized
+		// to '/'.
+		slashPos := strings.LastIndexByte(pattern, '/')
+		if slashPos != -1 && strings.HasPrefix(pattern[slashPos:], "/index.htm") {
+			normalised := pattern[:slashPos+1] + "$"
+			priority = s.MatchAllow(path, normalised)
+			if priority >= 0 {
+				return priority, normalised
+			}
+		}
+	}
+	return priority, ""
+}
+
+//
+
+// compiler is a ParseHandler that builds a Robots, grouping rules
+// using the same logic as RobotsMatcher.
+type compiler struct {
+	robots *Robots
+
+	current       *Group // Group currently receiving rules, or nil.
+	seenSeparator bool   // True if the current group has seen any rule.
+
+	rawValue string // Value of the current line, before escaping.
+}
+
+func (c *compiler) HandleRobotsStart() {
+	c.robots = &Robots{
+		profile: GoogleProfile,
+	}
+	c.current = nil
+	c.seenSeparator = false
+}
+
+func (c *compiler) HandleRobotsEnd() {
+	c.endGroup()
+}
+
+func (c *compiler) endGroup() {
+	if c.current != nil {
+		c.robots.groups = append(c.robots.groups, *c.current)
+	}
+	c.current = nil
+	c.seenSeparator = false
+}
+
+func (c *compiler) HandleUserAgent(lineNum int, value string) {
+	if c.seenSeparator {
+		c.endGroup()
+	}
+	if c.current == nil {
+		c.current = &Group{}
+	}
+	agent, global := groupAgent(value)
+	c.current.Agents = append(c.current.Agents, agent)
+	if global {
+		c.current.Global = true
+	} else if agent == "*" {
+		c.current.starToken = true
+	}
+}
+
+// groupAgent returns the product token of a User-agent value, or "*"
+// and true for the global agent.
+func groupAgent(value string) (agent string, global bool) {
+	return classifyUserAgent(value, DefaultOptions(), false)
+}
+
+func (c *compiler) HandleAllow(lineNum int, value string) {
+	c.addRule(AllowRule, lineNum, value)
+}
+
+func (c *compiler) HandleDisallow(lineNum int, value string) {
+	c.addRule(DisallowRule, lineNum, value)
+}
+
+func (c *compiler) addRule(typ RuleType, lineNum int, value string) {
+	if c.current == nil {
+		// Rules outside of groups are ignored.
+		return
+	}
+	c.seenSeparator = true
+	rule := Rule{
+		Type:    typ,
+		Pattern: value,
+		Line:    lineNum,
+	}
+	if c.rawValue != value {
+		rule.original = c.rawValue
+	}
+	c.current.Rules = append(c.current.Rules, rule)
+}
+
+func (c *compiler) HandleCrawlDelay(lineNum int, value string) {
+	if c.current == nil || c.current.HasCrawlDelay {
+		return
+	}
+	if delay, ok := parseCrawlDelay(value); ok {
+		c.current.CrawlDelay = delay
+		c.current.HasCrawlDelay = true
+	}
+}
+
+func (c *compiler) HandleRequestRate(lineNum int, value string) {
+	if c.current == nil {
+		return
+	}
+	if r, ok := parseRequestRate(value); ok {
+		c.current.RequestRates = append(c.current.RequestRates, r)
+	}
+}
+
+func (c *compiler) HandleVisitTime(lineNum int, value string) {
+	if c.current == nil {
+		return
+	}
+	if v, ok := parseVisitTime(value); ok {
+		c.current.VisitTimes = append(c.current.VisitTimes, v)
+	}
+}
+
+func (c *compiler) HandleContentSignal(lineNum int, value string) {
+	c.addUsageRule(lineNum, value)
+}
+
+func (c *compiler) HandleContentUsage(lineNum int, value string) {
+	c.addUsageRule(lineNum, value)
+}
+
+func (c *compiler) addUsageRule(lineNum int, value string) {
+	if c.current == nil {
+		return
+	}
+	if r, ok := parseUsageRule(lineNum, value); ok {
+		c.current.UsageRules = append(c.current.UsageRules, r)
+	}
+}
+
+func (c *compiler) HandleHost(lineNum int, value string) {
+	if value != "" {
+		c.robots.hosts = append(c.robots.hosts, value)
+	}
+}
+
+func (c *compiler) HandleCleanParam(lineNum int, value string) {
+	if p, ok := parseCleanParam(value); ok {
+		c.robots.cleanParams = append(c.robots.cleanParams, p)
+	}
+}
+
+func (c *compiler) HandleSitemap(lineNum int, value string) {
+	c.robots.sitemaps = append(c.robots.sitemaps, value)
+}
+
+func (c *compiler) HandleUnknownAction(lineNum int, action, value string) {}
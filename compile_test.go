@@ -0,0 +1,72 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compile", func() {
+
+	It("should group agents and rules", func() {
+		const robotstxt = "allow: /foo/bar/\n" +
+			"\n" +
+			"user-agent: FooBot\n" +
+			"user-agent: * ignored\n" +
+			"disallow: /\n" +
+			"allow: /x/\n" +
+			"Sitemap: http://foo.bar/sitemap.xml\n" +
+			"user-agent: BarBot/2.1\n" +
+			"disallow: /y/\n"
+
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Groups()).To(Equal([]grobotstxt.Group{
+			{
+				Agents: []string{"FooBot", "*"},
+				Rules: []grobotstxt.Rule{
+					{Type: grobotstxt.DisallowRule, Pattern: "/", Line: 5},
+					{Type: grobotstxt.AllowRule, Pattern: "/x/", Line: 6},
+				},
+				Global: true,
+			},
+			{
+				Agents: []string{"BarBot"},
+				Rules: []grobotstxt.Rule{
+					{Type: grobotstxt.DisallowRule, Pattern: "/y/", Line: 9},
+				},
+			},
+		}))
+		Expect(robots.Sitemaps()).To(Equal([]string{"http://foo.bar/sitemap.xml"}))
+	})
+
+	It("should not close groups on other lines", func() {
+		const robotstxt = "User-agent: FooBot\n" +
+			"Sitemap: https://foo.bar/sitemap\n" +
+			"Invalid-Unknown-Line: unknown\n" +
+			"User-agent: BarBot\n" +
+			"Disallow: /\n"
+
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Groups()).To(HaveLen(1))
+		Expect(robots.Allowed("FooBot", "http://foo.bar/")).To(BeFalse())
+		Expect(robots.Allowed("BarBot", "http://foo.bar/")).To(BeFalse())
+		Expect(robots.Allowed("BazBot", "http://foo.bar/")).To(BeTrue())
+	})
+
+	It("should compile an empty file", func() {
+		robots, err := grobotstxt.Compile("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Groups()).To(BeEmpty())
+		Expect(robots.Sitemaps()).To(BeEmpty())
+		Expect(robots.Allowed("FooBot", "http://foo.bar/")).To(BeTrue())
+	})
+
+	It("should disallow invalid URIs", func() {
+		robots, err := grobotstxt.Compile("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Allowed("FooBot", "http://foo.bar/%zz")).To(BeFalse())
+	})
+
+})
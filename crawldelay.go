@@ -0,0 +1,46 @@
+package grobotstxt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CrawlDelay parses the given robots.txt content, and returns the crawl
+// delay that applies to the given userAgents. See Checker.CrawlDelay.
+//
+// Unlike Allow and Disallow, a "Crawl-delay:" line does not close its
+// group, and applies to all of the group's user agents. Only the first
+// valid Crawl-delay of the applicable groups is used.
+func CrawlDelay(robotsBody string, userAgents []string) (delay time.Duration, specific bool, ok bool) {
+	c, err := NewChecker(robotsBody, userAgents...)
+	if err != nil {
+		return 0, false, false
+	}
+	return c.CrawlDelay()
+}
+
+// parseCrawlDelay parses the value of a "Crawl-delay:" line, which is a
+// non-negative number of seconds, and may be fractional (e.g. "0.5").
+// It returns false if the value is not a valid delay.
+func parseCrawlDelay(value string) (time.Duration, bool) {
+	// Some webmasters write a unit after the number, e.g. "10s" or "10 seconds".
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	if end == 0 {
+		return 0, false
+	}
+	if end != -1 {
+		value = value[:end]
+	}
+	secs, err := strconv.ParseFloat(value, 64)
+	if err != nil || secs < 0 || math.IsInf(secs, 0) || math.IsNaN(secs) {
+		return 0, false
+	}
+	if secs > math.MaxInt64/float64(time.Second) {
+		return time.Duration(math.MaxInt64), true
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
@@ -0,0 +1,77 @@
+package grobotstxt_test
+
+import (
+	"time"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CrawlDelay", func() {
+
+	type result struct {
+		delay    time.Duration
+		specific bool
+		ok       bool
+	}
+
+	crawlDelay := func(robotstxt string, userAgents ...string) result {
+		delay, specific, ok := grobotstxt.CrawlDelay(robotstxt, userAgents)
+		return result{delay, specific, ok}
+	}
+
+	It("should use the same groups as Allow and Disallow", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Crawl-delay: 10\n" +
+			"Disallow: /x/\n" +
+			"User-agent: FooBot\n" +
+			"Disallow: /x/\n" +
+			"User-agent: BarBot\n" +
+			"Crawl-delay: 2.5\n" +
+			"Disallow: /\n"
+
+		Expect(crawlDelay(robotstxt, "BazBot")).To(Equal(result{10 * time.Second, false, true}))
+		Expect(crawlDelay(robotstxt, "BarBot")).To(Equal(result{2500 * time.Millisecond, true, true}))
+		// A specific group without a Crawl-delay does not fall back to '*'.
+		Expect(crawlDelay(robotstxt, "FooBot")).To(Equal(result{0, true, false}))
+	})
+
+	It("should not close a group", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Crawl-delay: 5\n" +
+			"User-agent: FooBot\n" +
+			"Disallow: /\n"
+
+		Expect(crawlDelay(robotstxt, "FooBot")).To(Equal(result{5 * time.Second, true, true}))
+		Expect(crawlDelay(robotstxt, "BarBot")).To(Equal(result{5 * time.Second, false, true}))
+		Expect(grobotstxt.AgentAllowed(robotstxt, "BarBot", "http://foo.bar/")).To(BeFalse())
+	})
+
+	It("should ignore Crawl-delay outside groups, and invalid values", func() {
+		const robotstxt = "Crawl-delay: 1\n" +
+			"User-agent: *\n" +
+			"Crawl-delay: soon\n" +
+			"Crawl-delay: -1\n" +
+			"Crawl-delay: .5 seconds\n" +
+			"Crawl-delay: 7\n"
+
+		Expect(crawlDelay(robotstxt, "FooBot")).To(Equal(result{500 * time.Millisecond, false, true}))
+		Expect(crawlDelay("", "FooBot")).To(Equal(result{0, false, false}))
+	})
+
+	It("should accept common typos", func() {
+		for _, key := range []string{"crawl-delay", "CRAWL-DELAY", "crawldelay", "Crawl delay", "crawl_delay"} {
+			robotstxt := "User-agent: FooBot\n" + key + ": 3\n"
+			Expect(crawlDelay(robotstxt, "FooBot")).To(Equal(result{3 * time.Second, true, true}), key)
+		}
+	})
+
+	It("should still be an unknown action for other handlers", func() {
+		report := &robotsStatsReporter{}
+		grobotstxt.Parse("User-agent: FooBot\nCrawl-delay: 3\n", report)
+		Expect(report.validDirectives).To(Equal(1))
+		Expect(report.unknownDirectives).To(Equal(1))
+	})
+
+})
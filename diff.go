@@ -0,0 +1,261 @@
+package grobotstxt
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// RobotsDiff describes how crawl behaviour changes between two versions
+// of a robots.txt file. See Diff.
+type RobotsDiff struct {
+	// Agents holds the user agents whose rules or crawl delay changed.
+	// The global agent "*" comes first, then the others in alphabetical order.
+	Agents []AgentDiff
+
+	AddedSitemaps   []string
+	RemovedSitemaps []string
+
+	// Flips holds the sample URIs whose verdict changed, for each user agent.
+	Flips []Flip
+}
+
+// AgentDiff describes the changes to the rules that apply to a user agent.
+// For an agent that has no group of its own in a file, the rules of the
+// global '*' groups are used, as they are when matching.
+type AgentDiff struct {
+	Agent string
+
+	Added   []Rule       // Rules only in the new file.
+	Removed []Rule       // Rules only in the old file.
+	Changed []RuleChange // Rules whose pattern is in both files, but whose type changed.
+
+	// CrawlDelay is non-nil if the crawl delay changed.
+	CrawlDelay *CrawlDelayChange
+}
+
+// RuleChange is a rule whose type changed between two versions of a file.
+type RuleChange struct {
+	Old Rule
+	New Rule
+}
+
+// CrawlDelayChange is a change of crawl delay. OldOK and NewOK are false
+// where there is no crawl delay.
+type CrawlDelayChange struct {
+	Old, New     time.Duration
+	OldOK, NewOK bool
+}
+
+// Flip is a sample URI whose verdict changed for a user agent.
+type Flip struct {
+	URI     string
+	Agent   string
+	Allowed bool // The verdict for the new file.
+}
+
+// Empty returns true if there are no changes.
+func (d *RobotsDiff) Empty() bool {
+	return len(d.Agents) == 0 && len(d.AddedSitemaps) == 0 &&
+		len(d.RemovedSitemaps) == 0 && len(d.Flips) == 0
+}
+
+// String returns a human-readable report of the changes, one per line.
+func (d *RobotsDiff) String() string {
+	var sb strings.Builder
+	for _, s := range d.AddedSitemaps {
+		sb.WriteString("sitemap added: " + s + "\n")
+	}
+	for _, s := range d.RemovedSitemaps {
+		sb.WriteString("sitemap removed: " + s + "\n")
+	}
+	for _, a := range d.Agents {
+		sb.WriteString("user-agent " + a.Agent + ":\n")
+		for _, r := range a.Removed {
+			sb.WriteString("  - " + formatRule(r) + "\n")
+		}
+		for _, r := range a.Added {
+			sb.WriteString("  + " + formatRule(r) + "\n")
+		}
+		for _, c := range a.Changed {
+			sb.WriteString("  ~ " + formatRule(c.Old) + " -> " + formatRule(c.New) + "\n")
+		}
+		if c := a.CrawlDelay; c != nil {
+			sb.WriteString("  crawl-delay: " + formatDelay(c.Old, c.OldOK) + " -> " + formatDelay(c.New, c.NewOK) + "\n")
+		}
+	}
+	for _, f := range d.Flips {
+		verdict := "allowed -> disallowed"
+		if f.Allowed {
+			verdict = "disallowed -> allowed"
+		}
+		sb.WriteString("uri " + f.URI + " for " + f.Agent + ": " + verdict + "\n")
+	}
+	return sb.String()
+}
+
+func formatRule(r Rule) string {
+	return strings.TrimSpace(r.Type.String() + ": " + r.Pattern)
+}
+
+func formatDelay(d time.Duration, ok bool) string {
+	if !ok {
+		return "none"
+	}
+	return d.String()
+}
+
+// Diff parses two versions of a robots.txt file, and returns the changes
+// in crawl behaviour between them. See DiffRobots.
+func Diff(oldBody, newBody string, uris []string) (*RobotsDiff, error) {
+	oldRobots, err := Compile(oldBody)
+	if err != nil {
+		return nil, err
+	}
+	newRobots, err := Compile(newBody)
+	if err != nil {
+		return nil, err
+	}
+	return DiffRobots(oldRobots, newRobots, uris), nil
+}
+
+// DiffRobots returns the changes in crawl behaviour between two versions
+// of a robots.txt file. Rules are compared for every user agent named in
+// either file, and for the global agent "*". Each of the given sample URIs
+// is matched against both files, for each of those agents, and reported
+// if its verdict changed.
+//
+// Line numbers, comments, and the order of rules are ignored, as they do
+// not affect the verdict.
+func DiffRobots(oldRobots, newRobots *Robots, uris []string) *RobotsDiff {
+	d := &RobotsDiff{}
+	d.RemovedSitemaps, d.AddedSitemaps = diffStrings(oldRobots.sitemaps, newRobots.sitemaps)
+
+	agents := diffAgents(oldRobots, newRobots)
+	for _, agent := range agents {
+		oldChecker := oldRobots.Checker(agent)
+		newChecker := newRobots.Checker(agent)
+
+		a := AgentDiff{Agent: agent}
+		a.Removed, a.Added, a.Changed = diffRules(checkerRules(oldChecker), checkerRules(newChecker))
+		oldDelay, _, oldOK := oldChecker.CrawlDelay()
+		newDelay, _, newOK := newChecker.CrawlDelay()
+		if oldDelay != newDelay || oldOK != newOK {
+			a.CrawlDelay = &CrawlDelayChange{oldDelay, newDelay, oldOK, newOK}
+		}
+		if len(a.Added) > 0 || len(a.Removed) > 0 || len(a.Changed) > 0 || a.CrawlDelay != nil {
+			d.Agents = append(d.Agents, a)
+		}
+	}
+
+	for _, uri := range uris {
+		for _, agent := range agents {
+			allowed := newRobots.Allowed(agent, uri)
+			if oldRobots.Allowed(agent, uri) != allowed {
+				d.Flips = append(d.Flips, Flip{URI: uri, Agent: agent, Allowed: allowed})
+			}
+		}
+	}
+	return d
+}
+
+// diffAgents returns the global agent "*", followed by every agent named
+// in either file, in alphabetical order. Agents are compared ignoring case,
+// and the first spelling found is used.
+func diffAgents(robots ...*Robots) []string {
+	seen := map[string]bool{"*": true}
+	var agents []string
+	for _, r := range robots {
+		for _, g := range r.groups {
+			for _, agent := range g.Agents {
+				if agent == "" || seen[strings.ToLower(agent)] {
+					continue
+				}
+				seen[strings.ToLower(agent)] = true
+				agents = append(agents, agent)
+			}
+		}
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return strings.ToLower(agents[i]) < strings.ToLower(agents[j])
+	})
+	return append([]string{"*"}, agents...)
+}
+
+// checkerRules returns the rules that decide the verdicts of the Checker.
+func checkerRules(c *Checker) []Rule {
+	if c.everSeenSpecificAgent {
+		return c.specific
+	}
+	return c.global
+}
+
+// diffRules compares two sets of rules by type and pattern, ignoring line
+// numbers. Removed and added rules with the same pattern are reported as changed.
+func diffRules(oldRules, newRules []Rule) (removed, added []Rule, changed []RuleChange) {
+	type ruleKey struct {
+		typ     RuleType
+		pattern string
+	}
+	counts := make(map[ruleKey]int)
+	for _, r := range newRules {
+		counts[ruleKey{r.Type, r.Pattern}]++
+	}
+	for _, r := range oldRules {
+		k := ruleKey{r.Type, r.Pattern}
+		if counts[k] > 0 {
+			counts[k]--
+		} else {
+			removed = append(removed, r)
+		}
+	}
+	for _, r := range newRules {
+		k := ruleKey{r.Type, r.Pattern}
+		if counts[k] > 0 {
+			counts[k]--
+			added = append(added, r)
+		}
+	}
+
+	// Pair up removed and added rules with the same pattern.
+	var stillRemoved []Rule
+	for _, r := range removed {
+		paired := false
+		for i, a := range added {
+			if a.Pattern == r.Pattern {
+				changed = append(changed, RuleChange{Old: r, New: a})
+				added = append(added[:i], added[i+1:]...)
+				paired = true
+				break
+			}
+		}
+		if !paired {
+			stillRemoved = append(stillRemoved, r)
+		}
+	}
+	if len(added) == 0 {
+		added = nil
+	}
+	return stillRemoved, added, changed
+}
+
+// diffStrings returns the strings only in a, and those only in b.
+func diffStrings(a, b []string) (onlyA, onlyB []string) {
+	inA := make(map[string]bool)
+	for _, s := range a {
+		inA[s] = true
+	}
+	inB := make(map[string]bool)
+	for _, s := range b {
+		inB[s] = true
+		if !inA[s] {
+			onlyB = append(onlyB, s)
+		}
+	}
+	for _, s := range a {
+		if !inB[s] {
+			onlyA = append(onlyA, s)
+		}
+	}
+	return onlyA, onlyB
+}
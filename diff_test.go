@@ -0,0 +1,110 @@
+package grobotstxt_test
+
+import (
+	"time"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+
+	const oldRobots = "User-agent: *\n" +
+		"Disallow: /tmp/\n" +
+		"Crawl-delay: 1\n" +
+		"\n" +
+		"User-agent: FooBot\n" +
+		"Disallow: /private/\n" +
+		"Allow: /public/\n" +
+		"\n" +
+		"Sitemap: http://foo.bar/old.xml\n" +
+		"Sitemap: http://foo.bar/sitemap.xml\n"
+
+	const newRobots = "# Reordered, and reformatted.\n" +
+		"Sitemap: http://foo.bar/sitemap.xml\n" +
+		"User-agent: foobot\n" +
+		"Allow: /public/\n" +
+		"Disallow: /private/\n" +
+		"Disallow: /drafts/\n" +
+		"\n" +
+		"User-agent: *\n" +
+		"Allow: /tmp/\n" +
+		"Crawl-delay: 2\n" +
+		"\n" +
+		"User-agent: BarBot\n" +
+		"Disallow: /\n" +
+		"Sitemap: http://foo.bar/new.xml\n"
+
+	uris := []string{
+		"http://foo.bar/",
+		"http://foo.bar/tmp/x",
+		"http://foo.bar/drafts/x",
+	}
+
+	It("should report changes per user agent", func() {
+		d, err := grobotstxt.Diff(oldRobots, newRobots, uris)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Empty()).To(BeFalse())
+		Expect(d.AddedSitemaps).To(Equal([]string{"http://foo.bar/new.xml"}))
+		Expect(d.RemovedSitemaps).To(Equal([]string{"http://foo.bar/old.xml"}))
+
+		Expect(d.Agents).To(Equal([]grobotstxt.AgentDiff{
+			{
+				Agent: "*",
+				Changed: []grobotstxt.RuleChange{{
+					Old: grobotstxt.Rule{Type: grobotstxt.DisallowRule, Pattern: "/tmp/", Line: 2},
+					New: grobotstxt.Rule{Type: grobotstxt.AllowRule, Pattern: "/tmp/", Line: 9},
+				}},
+				CrawlDelay: &grobotstxt.CrawlDelayChange{
+					Old: time.Second, New: 2 * time.Second, OldOK: true, NewOK: true,
+				},
+			},
+			{
+				Agent: "BarBot",
+				Added: []grobotstxt.Rule{{Type: grobotstxt.DisallowRule, Pattern: "/", Line: 13}},
+				Removed: []grobotstxt.Rule{
+					{Type: grobotstxt.DisallowRule, Pattern: "/tmp/", Line: 2},
+				},
+				CrawlDelay: &grobotstxt.CrawlDelayChange{
+					Old: time.Second, OldOK: true,
+				},
+			},
+			{
+				Agent: "FooBot",
+				Added: []grobotstxt.Rule{{Type: grobotstxt.DisallowRule, Pattern: "/drafts/", Line: 6}},
+			},
+		}))
+
+		Expect(d.Flips).To(Equal([]grobotstxt.Flip{
+			{URI: "http://foo.bar/", Agent: "BarBot", Allowed: false},
+			{URI: "http://foo.bar/tmp/x", Agent: "*", Allowed: true},
+			{URI: "http://foo.bar/drafts/x", Agent: "BarBot", Allowed: false},
+			{URI: "http://foo.bar/drafts/x", Agent: "FooBot", Allowed: false},
+		}))
+
+		Expect(d.String()).To(Equal("sitemap added: http://foo.bar/new.xml\n" +
+			"sitemap removed: http://foo.bar/old.xml\n" +
+			"user-agent *:\n" +
+			"  ~ Disallow: /tmp/ -> Allow: /tmp/\n" +
+			"  crawl-delay: 1s -> 2s\n" +
+			"user-agent BarBot:\n" +
+			"  - Disallow: /tmp/\n" +
+			"  + Disallow: /\n" +
+			"  crawl-delay: 1s -> none\n" +
+			"user-agent FooBot:\n" +
+			"  + Disallow: /drafts/\n" +
+			"uri http://foo.bar/ for BarBot: allowed -> disallowed\n" +
+			"uri http://foo.bar/tmp/x for *: disallowed -> allowed\n" +
+			"uri http://foo.bar/drafts/x for BarBot: allowed -> disallowed\n" +
+			"uri http://foo.bar/drafts/x for FooBot: allowed -> disallowed\n"))
+	})
+
+	It("should report no changes for equivalent files", func() {
+		d, err := grobotstxt.Diff(oldRobots, grobotstxt.Format(oldRobots), uris)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Empty()).To(BeTrue())
+		Expect(d.String()).To(BeEmpty())
+	})
+
+})
@@ -0,0 +1,131 @@
+package grobotstxt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Scope is where a custom Directive applies.
+type Scope int
+
+// Scopes of directives.
+const (
+	// GroupScope directives apply to the user-agent group they are in,
+	// like Crawl-delay. They are ignored before the first User-agent line.
+	GroupScope Scope = iota
+	// GlobalScope directives apply to the whole file, like Sitemap,
+	// wherever they appear.
+	GlobalScope
+)
+
+// Directive declares a custom robots.txt directive, such as a vendor's
+// extension. See Directives.
+type Directive struct {
+	// Name is the directive's key, such as "X-Our-Priority",
+	// which is matched ignoring case.
+	Name string
+
+	// Aliases holds misspellings of Name that are also accepted, if
+	// Options.Typos is true, such as "X-Our-Priorty".
+	Aliases []string
+
+	Scope Scope
+
+	// Escape is true if the directive's value is a path pattern, to be
+	// escaped as Allow and Disallow values are: characters outside of
+	// US-ASCII are percent-encoded, and percent-encodings are uppercased.
+	Escape bool
+}
+
+// Directives is a registry of custom robots.txt directives, which teaches
+// a Parser or RobotsMatcher about them. See Options.Directives.
+//
+// Directives is safe for concurrent use by multiple goroutines.
+type Directives struct {
+	mu      sync.RWMutex
+	names   map[string]*Directive // Lower case name, to directive.
+	aliases map[string]*Directive // Lower case alias, to directive.
+}
+
+// NewDirectives returns a registry of the given directives.
+// It panics if any directive is invalid, as per Register.
+func NewDirectives(directives ...Directive) *Directives {
+	r := &Directives{
+		names:   make(map[string]*Directive),
+		aliases: make(map[string]*Directive),
+	}
+	for _, d := range directives {
+		if err := r.Register(d); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds the given directive to the registry. The name must be a
+// token, such as "X-Our-Priority", and aliases must not contain ':' or '#'.
+// Neither may already be registered, or be read as a standard key,
+// including its misspellings.
+func (r *Directives) Register(d Directive) error {
+	if !isDirectiveKey(d.Name, false) {
+		return fmt.Errorf("grobotstxt: invalid directive name %q", d.Name)
+	}
+	for _, alias := range d.Aliases {
+		if !isDirectiveKey(alias, true) {
+			return fmt.Errorf("grobotstxt: invalid alias %q of directive %q", alias, d.Name)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := append([]string{d.Name}, d.Aliases...)
+	for _, key := range keys {
+		if typ := parseKeyAs(key, googleKeys).Type(); typ != unknownKey {
+			return fmt.Errorf("grobotstxt: directive %q is read as %q", key, keyNames[typ])
+		}
+		lower := strings.ToLower(key)
+		if r.names[lower] != nil || r.aliases[lower] != nil {
+			return fmt.Errorf("grobotstxt: directive %q already registered", key)
+		}
+	}
+
+	d.Aliases = append([]string(nil), d.Aliases...)
+	r.names[strings.ToLower(d.Name)] = &d
+	for _, alias := range d.Aliases {
+		r.aliases[strings.ToLower(alias)] = &d
+	}
+	return nil
+}
+
+// isDirectiveKey returns true if key can be registered: a token,
+// or if spaces is true, tokens separated by single spaces.
+func isDirectiveKey(key string, spaces bool) bool {
+	if key == "" || key[0] == ' ' || key[len(key)-1] == ' ' || strings.Contains(key, "  ") {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c == '#' || !(asciiIsTokenChar(c) || c == ' ' && spaces) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup returns the directive with the given key, which may be an alias
+// if typos is true, or nil if there is none. It is nil-safe.
+func (r *Directives) lookup(key string, typos bool) *Directive {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	lower := strings.ToLower(key)
+	if d := r.names[lower]; d != nil {
+		return d
+	}
+	if typos {
+		return r.aliases[lower]
+	}
+	return nil
+}
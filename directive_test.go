@@ -0,0 +1,122 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// directiveRecorder is a ParseHandler that records custom directives,
+// and unknown actions.
+type directiveRecorder struct {
+	robotsStatsReporter
+	directives []string
+	unknown    []string
+}
+
+func (r *directiveRecorder) HandleDirective(lineNum int, d grobotstxt.Directive, value string) {
+	r.directives = append(r.directives, d.Name+"="+value)
+}
+
+func (r *directiveRecorder) HandleUnknownAction(lineNum int, action, value string) {
+	r.unknown = append(r.unknown, action+"="+value)
+}
+
+var _ = Describe("Directives", func() {
+
+	newDirectives := func() *grobotstxt.Directives {
+		return grobotstxt.NewDirectives(
+			grobotstxt.Directive{
+				Name:    "X-Our-Priority",
+				Aliases: []string{"X-Our-Priorty", "X Our Priority"},
+			},
+			grobotstxt.Directive{
+				Name:   "X-Mirror",
+				Scope:  grobotstxt.GlobalScope,
+				Escape: true,
+			},
+		)
+	}
+
+	parse := func(robotstxt string, opts grobotstxt.Options) *directiveRecorder {
+		r := &directiveRecorder{}
+		grobotstxt.NewParser(robotstxt, r, opts).Parse()
+		return r
+	}
+
+	const robotstxt = "X-Mirror: /spiegel/größe\n" +
+		"X-Our-Priority: 1\n" +
+		"User-agent: FooBot\n" +
+		"x-our-priority: 2\n" +
+		"X-Our-Priorty: 3\n" +
+		"X Our Priority: 4\n" +
+		"X-Other: 5\n"
+
+	It("should emit custom directives to the handler", func() {
+		opts := grobotstxt.DefaultOptions()
+		opts.Directives = newDirectives()
+		r := parse(robotstxt, opts)
+		Expect(r.directives).To(Equal([]string{
+			"X-Mirror=/spiegel/gr%C3%B6%C3%9Fe",
+			"X-Our-Priority=2",
+			"X-Our-Priority=3",
+			"X-Our-Priority=4",
+		}))
+		Expect(r.unknown).To(Equal([]string{"X-Other=5"}))
+	})
+
+	It("should only accept aliases with typos", func() {
+		opts := grobotstxt.Options{Directives: newDirectives()}
+		r := parse(robotstxt, opts)
+		Expect(r.directives).To(Equal([]string{
+			"X-Mirror=/spiegel/gr%C3%B6%C3%9Fe",
+			"X-Our-Priority=2",
+		}))
+		Expect(r.unknown).To(Equal([]string{"X-Our-Priorty=3", "X Our Priority=4", "X-Other=5"}))
+	})
+
+	It("should be unknown actions otherwise", func() {
+		r := parse(robotstxt, grobotstxt.DefaultOptions())
+		Expect(r.directives).To(BeEmpty())
+		Expect(r.unknown).To(HaveLen(6))
+
+		// Handlers without HandleDirective receive them as unknown actions.
+		opts := grobotstxt.DefaultOptions()
+		opts.Directives = newDirectives()
+		report := &robotsStatsReporter{}
+		grobotstxt.NewParser(robotstxt, report, opts).Parse()
+		Expect(report.validDirectives).To(Equal(1))
+		Expect(report.unknownDirectives).To(Equal(5))
+	})
+
+	It("should not change matching", func() {
+		opts := grobotstxt.DefaultOptions()
+		opts.Directives = newDirectives()
+		const robotstxt = "User-agent: FooBot\nX-Our-Priority: 1\nDisallow: /\n"
+		Expect(grobotstxt.NewRobotsMatcher(opts).AgentAllowed(robotstxt, "FooBot", "http://foo.bar/")).To(BeFalse())
+	})
+
+	It("should reject invalid directives", func() {
+		d := grobotstxt.NewDirectives()
+		for _, directive := range []grobotstxt.Directive{
+			{Name: ""},
+			{Name: "X Priority"},
+			{Name: "X:Priority"},
+			{Name: "X-Priority", Aliases: []string{" X-Priorty"}},
+			{Name: "X-Priority", Aliases: []string{"X#Priorty"}},
+			// Standard keys, and their typos and prefixes.
+			{Name: "Disallow"},
+			{Name: "Disallowed"},
+			{Name: "X-Priority", Aliases: []string{"useragent"}},
+		} {
+			Expect(d.Register(directive)).NotTo(Succeed(), directive.Name)
+		}
+
+		Expect(d.Register(grobotstxt.Directive{Name: "X-Priority"})).To(Succeed())
+		Expect(d.Register(grobotstxt.Directive{Name: "Hostname"})).To(Succeed())
+		Expect(d.Register(grobotstxt.Directive{Name: "x-priority"})).NotTo(Succeed())
+		Expect(d.Register(grobotstxt.Directive{Name: "X-Other", Aliases: []string{"X-PRIORITY"}})).NotTo(Succeed())
+		Expect(func() { grobotstxt.NewDirectives(grobotstxt.Directive{Name: "Allow"}) }).To(Panic())
+	})
+
+})
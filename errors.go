@@ -0,0 +1,50 @@
+package grobotstxt
+
+import "strconv"
+
+// ParseErrorKind classifies a ParseError.
+type ParseErrorKind int
+
+// Kinds of ParseError.
+const (
+	// InvalidSyntax is reported for a line that looked like a directive,
+	// but could not be split into a key and a value.
+	InvalidSyntax ParseErrorKind = iota + 1
+	// RuleOutsideGroup is reported when a matcher receives a rule while
+	// its state says a group applies, but not which one.
+	RuleOutsideGroup
+	// InvalidPath is reported when a path to be matched does not
+	// begin with '/'.
+	InvalidPath
+)
+
+// String returns a description of the kind of error.
+func (k ParseErrorKind) String() string {
+	switch k {
+	case InvalidSyntax:
+		return "invalid syntax"
+	case RuleOutsideGroup:
+		return "rule outside of any user-agent group"
+	case InvalidPath:
+		return "path must begin with '/'"
+	default:
+		return "unknown error"
+	}
+}
+
+// ParseError reports a violation of the parser's or the matcher's
+// internal invariants, where earlier versions of this package panicked.
+// The line concerned is skipped, and parsing continues.
+//
+// See Parser.Err and RobotsMatcher.Err.
+type ParseError struct {
+	Line int // Line number within the robots.txt file, or 0 if not applicable.
+	Kind ParseErrorKind
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return "robots.txt: " + e.Kind.String()
+	}
+	return "robots.txt line " + strconv.Itoa(e.Line) + ": " + e.Kind.String()
+}
@@ -0,0 +1,151 @@
+package grobotstxt_test
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// adversarialRobots is a corpus of hostile and malformed robots.txt bodies.
+var adversarialRobots = []string{
+	"",
+	"\x00",
+	"\xEF",
+	"\xEF\xBB",
+	"\xEF\xBB\xBF",
+	"\xEF\xBB\xBF\xEF\xBB\xBF",
+	"\r",
+	"\n",
+	"\r\n\r\n\n\r",
+	":",
+	"::::",
+	" : ",
+	"#",
+	"#:",
+	":#",
+	"*",
+	"$",
+	"%",
+	"%a",
+	"%zz",
+	"a b",
+	"a b c",
+	"a\tb",
+	" \t ",
+	"user-agent",
+	"user-agent:",
+	"user-agent: ",
+	"user-agent *",
+	"user-agent: *\tfoo",
+	"user-agent: \xff\xfe",
+	"user-agent: ツ",
+	"allow",
+	"allow:",
+	"disallow: /",
+	"disallow: %",
+	"disallow: /%",
+	"disallow: /%a",
+	"disallow: /%zz",
+	"user-agent: *\ndisallow: $",
+	"user-agent: *\ndisallow: *",
+	"user-agent: *\ndisallow: **********************************$",
+	"user-agent: *\nallow: /index.html\nallow: index.htm\nallow: /index.htm$",
+	"user-agent: *\nallow: /\xc3",
+	"crawl-delay: 1\nuser-agent: *\ncrawl-delay: 1e400\ncrawl-delay: NaN\ncrawl-delay: -Inf\ncrawl-delay: 99999999999999999999",
+	"user-agent: *\nrequest-rate: /\nrequest-rate: 1/\nrequest-rate: 1/0\nrequest-rate: 9999999999999999999/1s\nrequest-rate: 1/1s 9\nrequest-rate: 1/1s -",
+	"user-agent: *\nvisit-time: -\nvisit-time: 0000-\nvisit-time: ::::-::::\nvisit-time: 2400-2400",
+	"user-agent: *\ncontent-signal: =\ncontent-signal: /\ncontent-signal: = , ,=yes\ncontent-usage: * a=y",
+	"clean-param: &\nclean-param: & & &\nclean-param: a /\xff\nhost:",
+	strings.Repeat("a", 2083*8+10),
+	"user-agent: *\ndisallow: /" + strings.Repeat("*a", 5000) + "$",
+	strings.Repeat("user-agent: *\n", 1000),
+	strings.Repeat("\r", 1000),
+	strings.Repeat("disallow: /\n", 1000),
+}
+
+// fragments are combined at random to make more adversarial bodies.
+var fragments = []string{
+	"user-agent", "User-Agent", "useragent", "allow", "disallow", "DISALOW",
+	"sitemap", "crawl-delay", "request-rate", "visit-time", "content-signal",
+	"content-usage", "host", "clean-param", "foo", ":", " ", "\t", "\n", "\r",
+	"\r\n", "#", "*", "$", "/", "%", "%2f", "&", "=", "-", "1", "0.5", "ツ",
+	"\xEF", "\xBB", "\xBF", "\x00", "\xff", "FooBot", "index.html",
+}
+
+var _ = Describe("Errors", func() {
+
+	uris := []string{
+		"",
+		"/",
+		"http://foo.bar/",
+		"http://foo.bar/index.html",
+		"http://foo.bar/%zz",
+		"http://foo.bar/?a=b&c=d#e",
+		"//",
+		"ツ",
+		"http://[::1",
+		"*",
+	}
+	agents := []string{"", "*", "FooBot", "foobot/2.1", "ツ", "* x"}
+
+	exercise := func(robotstxt string) {
+		grobotstxt.Parse(robotstxt, &robotsStatsReporter{})
+		Expect(grobotstxt.ParseReader(strings.NewReader(robotstxt), &robotsStatsReporter{})).To(Succeed())
+		grobotstxt.Sitemaps(robotstxt)
+		grobotstxt.Host(robotstxt)
+		grobotstxt.CleanParams(robotstxt)
+		grobotstxt.CrawlDelay(robotstxt, agents)
+		grobotstxt.RequestRates(robotstxt, agents)
+		grobotstxt.VisitTimes(robotstxt, agents)
+
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		for _, uri := range uris {
+			robots.CleanURL(uri)
+			m := grobotstxt.NewRobotsMatcher()
+			for _, agent := range agents {
+				allowed := m.AgentAllowed(robotstxt, agent, uri)
+				Expect(robots.Allowed(agent, uri)).To(Equal(allowed))
+				grobotstxt.UsagePreferences(robotstxt, agent, uri)
+			}
+		}
+	}
+
+	It("should never panic on adversarial input", func() {
+		for _, robotstxt := range adversarialRobots {
+			Expect(func() { exercise(robotstxt) }).NotTo(Panic(), "%q", robotstxt)
+		}
+	})
+
+	It("should never panic on random input", func() {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 500; i++ {
+			var sb strings.Builder
+			for n := rnd.Intn(40); n > 0; n-- {
+				sb.WriteString(fragments[rnd.Intn(len(fragments))])
+			}
+			robotstxt := sb.String()
+			Expect(func() { exercise(robotstxt) }).NotTo(Panic(), "%q", robotstxt)
+		}
+	})
+
+	It("should report invalid URIs", func() {
+		m := grobotstxt.NewRobotsMatcher()
+		Expect(m.AgentAllowed("", "FooBot", "http://foo.bar/%zz")).To(BeFalse())
+		Expect(m.Err()).To(HaveOccurred())
+
+		Expect(m.AgentAllowed("", "FooBot", "http://foo.bar/")).To(BeTrue())
+		Expect(m.Err()).NotTo(HaveOccurred())
+	})
+
+	It("should format parse errors", func() {
+		err := &grobotstxt.ParseError{Line: 3, Kind: grobotstxt.InvalidSyntax}
+		Expect(err.Error()).To(Equal("robots.txt line 3: invalid syntax"))
+		err = &grobotstxt.ParseError{Kind: grobotstxt.InvalidPath}
+		Expect(err.Error()).To(Equal("robots.txt: path must begin with '/'"))
+	})
+
+})
@@ -38,3 +38,125 @@ func ExampleSitemaps() {
 	// Output:
 	// [http://example.net/sitemap.xml http://example.net/sitemap2.xml]
 }
+
+func ExampleCompile() {
+
+	robotsTxt := `
+	# robots.txt with restricted area
+
+	User-agent: *
+	Disallow: /members/*
+`
+	robots, err := grobotstxt.Compile(robotsTxt)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(robots.Allowed("FooBot/1.0", "http://example.net/members/index.html"))
+	fmt.Println(robots.Allowed("FooBot/1.0", "http://example.net/index.html"))
+
+	// Output:
+	// false
+	// true
+}
+
+func ExampleCrawlDelay() {
+
+	robotsTxt := `
+	User-agent: *
+	Crawl-delay: 2.5
+	Disallow: /members/*
+`
+	delay, specific, ok := grobotstxt.CrawlDelay(robotsTxt, []string{"FooBot"})
+	fmt.Println(delay, specific, ok)
+
+	// Output:
+	// 2.5s false true
+}
+
+func ExampleLint() {
+
+	robotsTxt := `
+	Disallow: /tmp/
+	User-agent: Googlebot/2.1
+	DISALOW /members/
+`
+	for _, d := range grobotstxt.Lint(robotsTxt) {
+		fmt.Println(d)
+	}
+
+	// Output:
+	// line 2: error: rule comes before any user-agent, and is ignored
+	// line 3: warning: user-agent "Googlebot/2.1" is matched as "Googlebot"
+	// line 4: warning: missing ':' after "DISALOW"
+	// line 4: warning: "DISALOW" is read as "Disallow"
+}
+
+func ExampleExplain() {
+
+	robotsTxt := `
+	User-agent: *
+	Disallow: /members/
+	Allow: /members/join
+`
+	d := grobotstxt.Explain(robotsTxt, []string{"FooBot"}, "http://example.net/members/join.html")
+	fmt.Println(d.Allowed, d.Line())
+	fmt.Println(d.Reason)
+
+	// Output:
+	// true 4
+	// in the global '*' groups, Allow "/members/join" (line 4) matches the path with priority 13, higher than Disallow "/members/" (line 3) with priority 9, so the URI is allowed
+}
+
+func ExampleCompileProfile() {
+
+	robotsTxt := `
+	User-agent: *
+	Allow: /members/
+	Disallow: /members/private/
+`
+	for _, profile := range grobotstxt.Profiles {
+		robots, err := grobotstxt.CompileProfile(robotsTxt, profile)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(profile.Name, robots.Allowed("FooBot", "http://example.net/members/private/a.html"))
+	}
+
+	// Output:
+	// Google false
+	// Bing false
+	// Yandex false
+	// Draft true
+}
+
+func ExampleAllowedByAgent() {
+
+	robotsTxt := `
+	User-agent: *
+	Disallow: /members/
+
+	User-agent: FooBot
+	Allow: /members/
+`
+	agents := []string{"FooBot", "BarBot"}
+	verdicts, err := grobotstxt.AllowedByAgent(robotsTxt, agents, "http://example.net/members/index.html")
+	if err != nil {
+		panic(err)
+	}
+	for _, agent := range agents {
+		fmt.Println(agent, verdicts[agent].Allowed, verdicts[agent].Line)
+	}
+
+	// Output:
+	// FooBot true 6
+	// BarBot false 3
+}
+
+func ExampleCrawlers_Match() {
+
+	header := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	fmt.Println(grobotstxt.DefaultCrawlers.Match(header))
+
+	// Output:
+	// [Googlebot]
+}
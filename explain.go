@@ -0,0 +1,199 @@
+package grobotstxt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Decision explains the verdict for a URI and a set of user agents:
+// which rule decided it, which rule it beat, and why.
+type Decision struct {
+	Allowed bool
+
+	// Specific is true if the robots.txt file has groups naming one of
+	// the user agents, in which case only those groups are used. Otherwise,
+	// the global '*' groups are used.
+	Specific bool
+
+	// Winner is the rule that decided the verdict, or nil if no rule matched.
+	// Its line is the one returned by RobotsMatcher.MatchingLine.
+	Winner *RuleMatch
+
+	// Loser is the best matching rule of the other type, which lost to
+	// Winner, or nil if there is none.
+	Loser *RuleMatch
+
+	// Reason is a human-readable description of the decision.
+	Reason string
+}
+
+// Line returns the line of the deciding rule, or 0 if no rule matched.
+func (d Decision) Line() int {
+	if d.Winner == nil {
+		return 0
+	}
+	return d.Winner.Line
+}
+
+// RuleMatch is a rule that matched a URI's path.
+type RuleMatch struct {
+	Rule
+
+	// Agents holds the agents of the group containing the rule.
+	Agents []string
+
+	// Source is the name of the Source the rule came from,
+	// for a merged Policy. Otherwise it is empty.
+	Source string
+
+	// Priority is the match priority given by the MatchStrategy.
+	Priority int
+
+	// Normalised is the pattern that matched, if the Google-specific
+	// normalisation of Allow patterns ending in 'index.htm' or 'index.html'
+	// was used. Otherwise it is empty.
+	Normalised string
+}
+
+// describe returns the rule in the form `Allow "/x/" (line 3)`.
+func (m *RuleMatch) describe() string {
+	s := fmt.Sprintf("%s %q (line %d)", m.Type, m.Original(), m.Line)
+	if m.Source != "" {
+		s = fmt.Sprintf("%s %q (line %d of %s)", m.Type, m.Original(), m.Line, m.Source)
+	}
+	if m.Normalised != "" {
+		s += fmt.Sprintf(", normalised to %q,", m.Normalised)
+	}
+	return s
+}
+
+// Explain parses the given robots.txt content, matches it against the
+// given userAgents and URI, and returns the decision, explaining the
+// verdict given by AgentsAllowed.
+func Explain(robotsBody string, userAgents []string, uri string) Decision {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return Decision{Reason: "the robots.txt file cannot be parsed: " + err.Error()}
+	}
+	return r.Explain(userAgents, uri)
+}
+
+// Explain returns the decision for the given userAgents and URI,
+// explaining the verdict given by AgentsAllowed.
+func (r *Robots) Explain(userAgents []string, uri string) Decision {
+	path, ok := normalisedPath(uri)
+	if !ok {
+		return Decision{Reason: "the URI cannot be parsed, so it is disallowed"}
+	}
+
+	specific, global := r.selectGroups(userAgents)
+	d := Decision{Specific: len(specific) > 0}
+	groups := global
+	scope := "the global '*' groups"
+	if d.Specific {
+		groups = specific
+		scope = "the groups for " + strings.Join(userAgents, ", ")
+	}
+
+	// Find the best Allow and Disallow matches, as RobotsMatcher does:
+	// the first rule with the highest priority wins.
+	var allow, disallow *RuleMatch
+	for _, g := range groups {
+		for _, rule := range g.Rules {
+			var priority int
+			var normalised string
+			best := &disallow
+			if rule.Type == AllowRule {
+				priority, normalised = r.profile.allowPriority(path, rule.Pattern)
+				best = &allow
+			} else {
+				priority = r.profile.MatchStrategy.MatchDisallow(path, rule.Pattern)
+			}
+			if priority < 0 || (*best != nil && (*best).Priority >= priority) {
+				continue
+			}
+			if r.profile.Rules == FirstMatch && (priority == 0 || (*best != nil && (*best).Priority > 0)) {
+				// Only the first non-empty match counts.
+				continue
+			}
+			*best = &RuleMatch{
+				Rule:       rule,
+				Agents:     g.Agents,
+				Source:     g.source,
+				Priority:   priority,
+				Normalised: normalised,
+			}
+		}
+	}
+
+	allowPri, disallowPri := noMatchPriority, noMatchPriority
+	if allow != nil {
+		allowPri = allow.Priority
+	}
+	if disallow != nil {
+		disallowPri = disallow.Priority
+	}
+
+	switch {
+	case len(groups) == 0:
+		d.Allowed = true
+		d.Reason = "no group applies to the user agents, so the URI is allowed"
+
+	case r.profile.Rules == FirstMatch && allowPri > 0 && disallowPri > 0:
+		d.Winner, d.Loser = disallow, allow
+		if allow.Line < disallow.Line {
+			d.Winner, d.Loser = allow, disallow
+		}
+		d.Allowed = d.Winner == allow
+		verdict := "disallowed"
+		if d.Allowed {
+			verdict = "allowed"
+		}
+		d.Reason = fmt.Sprintf("in %s, %s is the first rule to match the path, before %s, so the URI is %s",
+			scope, d.Winner.describe(), d.Loser.describe(), verdict)
+
+	case r.profile.Rules == LongestMatchDisallowWins && disallowPri > 0 && disallowPri == allowPri:
+		d.Winner, d.Loser = disallow, allow
+		d.Reason = fmt.Sprintf("in %s, %s and %s match the path with equal priority %d, so the Disallow wins, and the URI is disallowed",
+			scope, disallow.describe(), allow.describe(), disallowPri)
+
+	case disallowPri > 0 && disallowPri > allowPri:
+		d.Winner, d.Loser = disallow, allow
+		if allow == nil {
+			d.Reason = fmt.Sprintf("in %s, %s matches the path, so the URI is disallowed",
+				scope, disallow.describe())
+		} else {
+			d.Reason = fmt.Sprintf("in %s, %s matches the path with priority %d, higher than %s with priority %d, so the URI is disallowed",
+				scope, disallow.describe(), disallowPri, allow.describe(), allowPri)
+		}
+
+	case allowPri > 0:
+		d.Allowed = true
+		d.Winner, d.Loser = allow, disallow
+		switch {
+		case disallow == nil:
+			d.Reason = fmt.Sprintf("in %s, %s matches the path, so the URI is allowed",
+				scope, allow.describe())
+		case allowPri == disallowPri:
+			d.Reason = fmt.Sprintf("in %s, %s and %s match the path with equal priority %d, so the less restrictive Allow wins, and the URI is allowed",
+				scope, allow.describe(), disallow.describe(), allowPri)
+		default:
+			d.Reason = fmt.Sprintf("in %s, %s matches the path with priority %d, higher than %s with priority %d, so the URI is allowed",
+				scope, allow.describe(), allowPri, disallow.describe(), disallowPri)
+		}
+
+	default:
+		d.Allowed = true
+		if disallowPri > allowPri {
+			d.Winner = disallow
+		} else {
+			d.Winner = allow
+		}
+		if d.Winner != nil {
+			d.Reason = fmt.Sprintf("in %s, only empty patterns match the path, so the URI is allowed", scope)
+		} else {
+			d.Reason = fmt.Sprintf("in %s, no rule matches the path, so the URI is allowed", scope)
+		}
+	}
+	return d
+}
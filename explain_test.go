@@ -0,0 +1,103 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Explain", func() {
+
+	const robotstxt = "User-agent: *\n" +
+		"Disallow: /x/\n" +
+		"Allow: /x/public\n" +
+		"Disallow: /empty\n" +
+		"Disallow:\n" +
+		"\n" +
+		"User-agent: FooBot\n" +
+		"User-agent: BarBot\n" +
+		"Disallow: /\n" +
+		"Allow: /fish\n" +
+		"Disallow: /fish\n" +
+		"Allow: /säle/index.html\n"
+
+	uris := []string{
+		"http://foo.bar/",
+		"http://foo.bar/x/",
+		"http://foo.bar/x/public/page",
+		"http://foo.bar/y/",
+		"http://foo.bar/fish",
+		"http://foo.bar/s%C3%A4le/",
+		"http://foo.bar/s%C3%A4le/other",
+		"http://foo.bar/%zz",
+	}
+
+	It("should agree with RobotsMatcher", func() {
+		for _, agent := range []string{"FooBot", "BazBot", ""} {
+			for _, uri := range uris {
+				m := grobotstxt.NewRobotsMatcher()
+				allowed := m.AgentAllowed(robotstxt, agent, uri)
+				d := grobotstxt.Explain(robotstxt, []string{agent}, uri)
+				Expect(d.Allowed).To(Equal(allowed), agent+" "+uri)
+				Expect(d.Line()).To(Equal(m.MatchingLine()), agent+" "+uri)
+				Expect(d.Specific).To(Equal(m.EverSeenSpecificAgent()), agent+" "+uri)
+				Expect(d.Reason).NotTo(BeEmpty())
+			}
+		}
+	})
+
+	It("should report the winning and losing rules", func() {
+		d := grobotstxt.Explain(robotstxt, []string{"BazBot"}, "http://foo.bar/x/public/page")
+		Expect(d.Allowed).To(BeTrue())
+		Expect(d.Specific).To(BeFalse())
+		Expect(d.Winner).To(Equal(&grobotstxt.RuleMatch{
+			Rule:     grobotstxt.Rule{Type: grobotstxt.AllowRule, Pattern: "/x/public", Line: 3},
+			Agents:   []string{"*"},
+			Priority: 9,
+		}))
+		Expect(d.Loser).To(Equal(&grobotstxt.RuleMatch{
+			Rule:     grobotstxt.Rule{Type: grobotstxt.DisallowRule, Pattern: "/x/", Line: 2},
+			Agents:   []string{"*"},
+			Priority: 3,
+		}))
+		Expect(d.Reason).To(Equal(`in the global '*' groups, Allow "/x/public" (line 3) matches the path with priority 9, ` +
+			`higher than Disallow "/x/" (line 2) with priority 3, so the URI is allowed`))
+	})
+
+	It("should report ties", func() {
+		d := grobotstxt.Explain(robotstxt, []string{"FooBot"}, "http://foo.bar/fish")
+		Expect(d.Allowed).To(BeTrue())
+		Expect(d.Specific).To(BeTrue())
+		Expect(d.Winner.Line).To(Equal(10))
+		Expect(d.Winner.Agents).To(Equal([]string{"FooBot", "BarBot"}))
+		Expect(d.Loser.Line).To(Equal(11))
+		Expect(d.Reason).To(ContainSubstring("equal priority 5"))
+	})
+
+	It("should report index.html normalisation and original patterns", func() {
+		d := grobotstxt.Explain(robotstxt, []string{"FooBot"}, "http://foo.bar/s%C3%A4le/")
+		Expect(d.Allowed).To(BeTrue())
+		Expect(d.Winner.Original()).To(Equal("/säle/index.html"))
+		Expect(d.Winner.Pattern).To(Equal("/s%C3%A4le/index.html"))
+		Expect(d.Winner.Normalised).To(Equal("/s%C3%A4le/$"))
+		Expect(d.Loser.Line).To(Equal(9))
+		Expect(d.Reason).To(ContainSubstring(`normalised to "/s%C3%A4le/$"`))
+	})
+
+	It("should explain when no rule decides", func() {
+		d := grobotstxt.Explain(robotstxt, []string{"BazBot"}, "http://foo.bar/y/")
+		Expect(d.Allowed).To(BeTrue())
+		Expect(d.Winner.Line).To(Equal(5))
+		Expect(d.Reason).To(ContainSubstring("only empty patterns"))
+
+		d = grobotstxt.Explain("", []string{"BazBot"}, "http://foo.bar/")
+		Expect(d.Allowed).To(BeTrue())
+		Expect(d.Winner).To(BeNil())
+		Expect(d.Reason).To(Equal("no group applies to the user agents, so the URI is allowed"))
+
+		d = grobotstxt.Explain(robotstxt, []string{"BazBot"}, "http://foo.bar/%zz")
+		Expect(d.Allowed).To(BeFalse())
+		Expect(d.Reason).To(ContainSubstring("cannot be parsed"))
+	})
+
+})
@@ -0,0 +1,176 @@
+package grobotstxt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Fallbacks is a table of user-agent fallback chains. When a robots.txt
+// file has no group for a user agent, the groups for its fallback are used
+// instead, and so on along the chain, before the global '*' groups.
+//
+// Fallbacks is safe for concurrent use by multiple goroutines.
+type Fallbacks struct {
+	mu      sync.RWMutex
+	parents map[string]string // Lower case user agent, to its fallback.
+}
+
+// NewFallbacks returns an empty table of fallbacks.
+func NewFallbacks() *Fallbacks {
+	return &Fallbacks{parents: make(map[string]string)}
+}
+
+// DefaultFallbacks holds the fallbacks documented for well-known crawler
+// families, such as Googlebot-Image to Googlebot, and bingbot to msnbot.
+// The table is not exhaustive, and more can be registered.
+var DefaultFallbacks = newDefaultFallbacks()
+
+func newDefaultFallbacks() *Fallbacks {
+	f := NewFallbacks()
+	for agent, parent := range map[string]string{
+		// Google.
+		"Googlebot-Image": "Googlebot",
+		"Googlebot-News":  "Googlebot",
+		"Googlebot-Video": "Googlebot",
+		// Bing.
+		"bingbot": "msnbot",
+		// Yandex.
+		"YandexBot":       "Yandex",
+		"YandexImages":    "Yandex",
+		"YandexVideo":     "Yandex",
+		"YandexNews":      "Yandex",
+		"YandexMobileBot": "Yandex",
+		// Baidu.
+		"Baiduspider-image": "Baiduspider",
+		"Baiduspider-video": "Baiduspider",
+		"Baiduspider-news":  "Baiduspider",
+	} {
+		if err := f.Register(agent, parent); err != nil {
+			panic(err)
+		}
+	}
+	return f
+}
+
+// Register makes parent the fallback for agent, replacing any previous one.
+// Both must be valid product tokens, such as "FooBot" or "FooBot-Image".
+// It returns an error if the fallback would make a cycle.
+func (f *Fallbacks) Register(agent, parent string) error {
+	for _, a := range []string{agent, parent} {
+		if a == "*" || !isValidUserAgentToObey(a) {
+			return fmt.Errorf("grobotstxt: invalid user-agent %q", a)
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, a := range f.chain(parent) {
+		if equalsIgnoreCase(a, agent) {
+			return fmt.Errorf("grobotstxt: fallback from %q to %q makes a cycle", agent, parent)
+		}
+	}
+	f.parents[strings.ToLower(agent)] = parent
+	return nil
+}
+
+// Chain returns the given user agent, followed by its fallbacks, in order.
+// A nil *Fallbacks has no fallbacks.
+func (f *Fallbacks) Chain(agent string) []string {
+	if f == nil {
+		return []string{agent}
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.chain(agent)
+}
+
+func (f *Fallbacks) chain(agent string) []string {
+	chain := []string{agent}
+	for {
+		parent, ok := f.parents[strings.ToLower(agent)]
+		if !ok {
+			return chain
+		}
+		chain = append(chain, parent)
+		agent = parent
+	}
+}
+
+// resolve returns the user agents whose groups apply: the given userAgents,
+// if any of them are present in the file, or else their fallbacks, if any
+// of those are present, and so on. If none are present, userAgents is
+// returned, and the global groups apply.
+func (f *Fallbacks) resolve(userAgents []string, present func(agent string) bool) []string {
+	chains := make([][]string, len(userAgents))
+	for i, agent := range userAgents {
+		chains[i] = f.Chain(agent)
+	}
+	for level := 0; ; level++ {
+		var agents []string
+		found := false
+		for _, chain := range chains {
+			if level < len(chain) {
+				agents = append(agents, chain[level])
+				found = found || present(chain[level])
+			}
+		}
+		if len(agents) == 0 {
+			return userAgents
+		}
+		if found {
+			return agents
+		}
+	}
+}
+
+// FallbackChecker is like Checker, but if the robots.txt file has no group
+// for the given user agents, the groups for their fallbacks are used.
+// See RobotsMatcher.Fallbacks.
+func (r *Robots) FallbackChecker(f *Fallbacks, userAgents ...string) *Checker {
+	agents := f.resolve(userAgents, func(agent string) bool {
+		specific, _ := r.selectGroups([]string{agent})
+		return len(specific) > 0
+	})
+	return r.Checker(agents...)
+}
+
+// namedAgents parses the given robots.txt body, and returns a func
+// reporting whether any User-agent line names the given agent, as
+// HandleUserAgent would match it.
+func (m *RobotsMatcher) namedAgents(robotsBody memBody) func(agent string) bool {
+	c := &agentCollector{opts: m.options(), strict: m.Strict}
+	p := newParser(robotsBody, c, m.opts)
+	p.Strict = m.Strict
+	p.MaxBytes = m.MaxBytes
+	p.Parse()
+	return func(agent string) bool {
+		for _, a := range c.agents {
+			if equalsIgnoreCase(a, agent) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// agentCollector is a ParseHandler that collects the product tokens of
+// User-agent lines, other than the global agent.
+type agentCollector struct {
+	opts   Options // As restricted by strict.
+	strict bool
+	agents []string
+}
+
+func (c *agentCollector) HandleRobotsStart() {}
+func (c *agentCollector) HandleRobotsEnd()   {}
+
+func (c *agentCollector) HandleUserAgent(lineNum int, value string) {
+	if agent, global := classifyUserAgent(value, c.opts, c.strict); !global && agent != "" {
+		c.agents = append(c.agents, agent)
+	}
+}
+
+func (c *agentCollector) HandleAllow(lineNum int, value string)                 {}
+func (c *agentCollector) HandleDisallow(lineNum int, value string)              {}
+func (c *agentCollector) HandleSitemap(lineNum int, value string)               {}
+func (c *agentCollector) HandleUnknownAction(lineNum int, action, value string) {}
@@ -0,0 +1,102 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fallbacks", func() {
+
+	const robotstxt = "User-agent: *\n" +
+		"Disallow: /private/\n" +
+		"\n" +
+		"User-agent: Googlebot\n" +
+		"Disallow: /nogoogle/\n" +
+		"\n" +
+		"User-agent: Googlebot-News\n" +
+		"Disallow: /nonews/\n"
+
+	// allowed returns the verdicts of a RobotsMatcher and a Checker,
+	// with the given fallbacks, and checks that they agree.
+	allowed := func(f *grobotstxt.Fallbacks, agent, path string) bool {
+		uri := "http://foo.bar" + path
+		m := grobotstxt.NewRobotsMatcher()
+		m.Fallbacks = f
+		verdict := m.AgentAllowed(robotstxt, agent, uri)
+
+		r, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.FallbackChecker(f, agent).Allowed(uri)).To(Equal(verdict), agent+" "+path)
+		return verdict
+	}
+
+	It("should use the most specific group present", func() {
+		f := grobotstxt.DefaultFallbacks
+		for _, test := range []struct {
+			agent, path string
+			allowed     bool
+		}{
+			{"Googlebot-Image", "/nogoogle/", false},
+			{"Googlebot-Image", "/private/", true},
+			{"Googlebot-News", "/nonews/", false},
+			{"Googlebot-News", "/nogoogle/", true},
+			{"Googlebot", "/nogoogle/", false},
+			{"BarBot", "/private/", false},
+			{"BarBot", "/nogoogle/", true},
+		} {
+			Expect(allowed(f, test.agent, test.path)).To(Equal(test.allowed), test.agent+" "+test.path)
+		}
+
+		// Without fallbacks, Googlebot-Image obeys the global group.
+		Expect(allowed(nil, "Googlebot-Image", "/nogoogle/")).To(BeTrue())
+		Expect(allowed(nil, "Googlebot-Image", "/private/")).To(BeFalse())
+	})
+
+	It("should return chains", func() {
+		Expect(grobotstxt.DefaultFallbacks.Chain("googlebot-image")).To(Equal([]string{"googlebot-image", "Googlebot"}))
+		Expect(grobotstxt.DefaultFallbacks.Chain("FooBot")).To(Equal([]string{"FooBot"}))
+	})
+
+	It("should register custom families", func() {
+		f := grobotstxt.NewFallbacks()
+		Expect(f.Register("FooBot-Image", "FooBot-Media")).To(Succeed())
+		Expect(f.Register("FooBot-Media", "FooBot")).To(Succeed())
+		Expect(f.Chain("FooBot-Image")).To(Equal([]string{"FooBot-Image", "FooBot-Media", "FooBot"}))
+
+		const robotstxt = "User-agent: *\n" +
+			"Disallow: /\n" +
+			"\n" +
+			"User-agent: FooBot\n" +
+			"Disallow: /nofoo/\n"
+		m := grobotstxt.NewRobotsMatcher()
+		m.Fallbacks = f
+		Expect(m.AgentAllowed(robotstxt, "FooBot-Image", "http://foo.bar/x")).To(BeTrue())
+		Expect(m.AgentAllowed(robotstxt, "FooBot-Image", "http://foo.bar/nofoo/")).To(BeFalse())
+		Expect(m.EverSeenSpecificAgent()).To(BeTrue())
+		Expect(m.MatchingLine()).To(Equal(5))
+
+		Expect(f.Register("FooBot", "FooBot-Image")).NotTo(Succeed())
+		Expect(f.Register("FooBot", "foobot")).NotTo(Succeed())
+		Expect(f.Register("FooBot/1.0", "FooBot")).NotTo(Succeed())
+		Expect(f.Register("FooBot", "*")).NotTo(Succeed())
+	})
+
+	It("should resolve several user agents together", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Disallow: /\n" +
+			"\n" +
+			"User-agent: Googlebot\n" +
+			"Disallow: /nogoogle/\n" +
+			"\n" +
+			"User-agent: msnbot\n" +
+			"Disallow: /nomsn/\n"
+		m := grobotstxt.NewRobotsMatcher()
+		m.Fallbacks = grobotstxt.DefaultFallbacks
+		agents := []string{"Googlebot-Image", "bingbot"}
+		Expect(m.AgentsAllowed(robotstxt, agents, "http://foo.bar/x")).To(BeTrue())
+		Expect(m.AgentsAllowed(robotstxt, agents, "http://foo.bar/nogoogle/")).To(BeFalse())
+		Expect(m.AgentsAllowed(robotstxt, agents, "http://foo.bar/nomsn/")).To(BeFalse())
+	})
+
+})
@@ -0,0 +1,198 @@
+package grobotstxt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Format returns the given robots.txt content in canonical form:
+//
+//   - Known keys are spelled in the standard way, e.g. "DISALOW" becomes "Disallow".
+//   - Keys and values are separated by ": ", and indentation is removed.
+//   - Patterns are percent-encoded, as they are by the parser.
+//   - The User-agent lines of a group are placed together, before the
+//     group's other lines, and groups are separated by a blank line.
+//   - Comments are preserved, and runs of blank lines are collapsed.
+//   - Lines end with "\n", and any byte order mark is removed, where possible.
+//
+// Formatting never changes the meaning of the file: the formatted content
+// gives the same verdicts as the original for every user agent and URI.
+// Lines that the parser truncates are left as they are, and content after
+// the first DefaultMaxBytes bytes, which the parser ignores, is commented
+// out. Content that formatting would grow past DefaultMaxBytes is returned
+// unchanged.
+func Format(robotsBody string) string {
+	return ParseTree(robotsBody).Format()
+}
+
+// Format returns the tree's robots.txt content in canonical form. See Format.
+func (t *Tree) Format() string {
+	items := make([]formatItem, 0, len(t.Nodes))
+	for _, n := range t.Nodes {
+		items = append(items, newFormatItem(n))
+	}
+	items = groupAgents(items)
+
+	// Mark where blank lines separate groups: before a User-agent line that
+	// follows other directives, and before any comments attached to it.
+	blankBefore := make([]bool, len(items)+1)
+	prevKey := unknownKey
+	for i, it := range items {
+		if it.kind != DirectiveNode {
+			continue
+		}
+		if it.key == userAgentKey && prevKey != userAgentKey {
+			j := i
+			for j > 0 && items[j-1].kind == CommentNode {
+				j--
+			}
+			blankBefore[j] = true
+		}
+		prevKey = it.key
+	}
+
+	var lines []string
+	blank := false
+	for i, it := range items {
+		if blankBefore[i] || it.kind == BlankNode {
+			blank = true
+		}
+		if it.kind == BlankNode {
+			continue
+		}
+		if blank && len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		blank = false
+		lines = append(lines, it.text)
+	}
+
+	var s string
+	if len(lines) > 0 {
+		s = strings.Join(lines, "\n") + "\n"
+		if s[0] == utfBOM[0] {
+			// The parser would skip the start of the first line as a byte
+			// order mark, so it needs a real one in front of it.
+			s = utfBOM + s
+		}
+	}
+
+	// Moving directives past DefaultMaxBytes would hide them from the
+	// parser, so content that grows that long is left as it is. Only its
+	// final line ending may fall past the cut.
+	if len(s) > DefaultMaxBytes+1 {
+		return t.String()
+	}
+	// Formatting may also shrink the content, which would bring what the
+	// parser ignores after DefaultMaxBytes within its reach.
+	return s + commentOut(t.Overflow, s != "")
+}
+
+// commentOut returns the non-blank lines of the given text as comments,
+// after a blank line if blank is true.
+func commentOut(text string, blank bool) string {
+	var sb strings.Builder
+	for _, line := range strings.FieldsFunc(text, isLineEnd) {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if blank {
+			sb.WriteString("\n")
+			blank = false
+		}
+		if line[0] != '#' {
+			line = "# " + line
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// isLineEnd returns true for the characters that end a line.
+func isLineEnd(r rune) bool {
+	return r == '\n' || r == '\r'
+}
+
+// formatItem is a formatted line.
+type formatItem struct {
+	kind NodeKind
+	key  keyType // Key type of a DirectiveNode.
+	text string
+}
+
+func newFormatItem(n *Node) formatItem {
+	it := formatItem{kind: n.Kind}
+	if n.Kind == DirectiveNode {
+		it.key = parseKey(n.Key).Type()
+	}
+	comment := strings.TrimRightFunc(n.Comment, unicode.IsSpace)
+	switch n.Kind {
+	case CommentNode:
+		it.text = comment
+	case DirectiveNode:
+		key := n.CanonicalKey()
+		if key == "" {
+			key = n.Key
+		}
+		it.text = key + ":"
+		if value := n.ParsedValue(); value != "" {
+			it.text += " " + value
+		}
+	case InvalidNode:
+		it.text = n.Value
+	}
+	if n.Kind != CommentNode && comment != "" {
+		it.text += " " + comment
+	}
+
+	// Moving text across the maximum line length would change what the
+	// parser sees, so such lines are left as they are.
+	if n.Overflow != "" || len(it.text) > DefaultMaxLineLen {
+		s := n.String()
+		it.text = s[:len(s)-len(n.EOL)]
+	}
+	return it
+}
+
+// groupAgents moves the User-agent lines of each group, and the comments
+// directly before them, ahead of any other lines that come between them,
+// such as Sitemap or Crawl-delay. These lines do not end a group,
+// so the meaning of the file is unchanged.
+func groupAgents(items []formatItem) []formatItem {
+	out := make([]formatItem, 0, len(items))
+	for i := 0; i < len(items); {
+		if items[i].key != userAgentKey {
+			out = append(out, items[i])
+			i++
+			continue
+		}
+		// The group's agents end at its first rule.
+		end := i
+		for end < len(items) && !isRuleKey(items[end].key) {
+			end++
+		}
+		var agents, others []formatItem
+		start := i
+		for j := i; j < end; j++ {
+			if items[j].key == userAgentKey {
+				// Keep comments directly before the agent with it.
+				k := j
+				for k > start && items[k-1].kind == CommentNode {
+					k--
+				}
+				others = append(others, items[start:k]...)
+				agents = append(agents, items[k:j+1]...)
+				start = j + 1
+			}
+		}
+		others = append(others, items[start:end]...)
+		out = append(out, agents...)
+		out = append(out, others...)
+		i = end
+	}
+	return out
+}
+
+func isRuleKey(key keyType) bool {
+	return key == allowKey || key == disallowKey
+}
@@ -0,0 +1,124 @@
+package grobotstxt_test
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Format", func() {
+
+	It("should format into canonical form", func() {
+		const robotstxt = "\xEF\xBB\xBF# Header.   \r\n" +
+			"\r\n" +
+			"\r\n" +
+			"  useragent FooBot\r\n" +
+			"Sitemap : http://foo.bar/sitemap.xml\r\n" +
+			"# Bar.\r\n" +
+			"USER-AGENT:BarBot   # Trailing.\r\n" +
+			"DISALOW /säle/%2f\r\n" +
+			"allow:\n" +
+			"user agent: *\n" +
+			"Crawl delay: 1\n" +
+			"not a directive\n" +
+			"X-Unknown:   value\n" +
+			"\n"
+
+		Expect(grobotstxt.Format(robotstxt)).To(Equal("# Header.\n" +
+			"\n" +
+			"User-agent: FooBot\n" +
+			"# Bar.\n" +
+			"User-agent: BarBot # Trailing.\n" +
+			"Sitemap: http://foo.bar/sitemap.xml\n" +
+			"Disallow: /s%C3%A4le/%2F\n" +
+			"Allow:\n" +
+			"\n" +
+			"User-agent: *\n" +
+			"Crawl-delay: 1\n" +
+			"not a directive\n" +
+			"X-Unknown: value\n"))
+	})
+
+	It("should format an empty file", func() {
+		Expect(grobotstxt.Format("")).To(Equal(""))
+		Expect(grobotstxt.Format("\n\n  \n")).To(Equal(""))
+	})
+
+	It("should leave truncated lines as they are", func() {
+		long := "Disallow:   /" + strings.Repeat("a", 20000)
+		Expect(grobotstxt.Format("User-agent: *\n" + long)).To(Equal("User-agent: *\n" + long + "\n"))
+	})
+
+	uris := []string{
+		"http://foo.bar/",
+		"http://foo.bar/x/",
+		"http://foo.bar/index.html",
+		"http://foo.bar/s%C3%A4le/",
+		"http://foo.bar/a",
+	}
+	agents := []string{"FooBot", "BarBot", "*", "ツ"}
+
+	check := func(robotstxt string) {
+		formatted := grobotstxt.Format(robotstxt)
+		Expect(grobotstxt.Format(formatted)).To(Equal(formatted), "%q", robotstxt)
+		for _, agent := range agents {
+			for _, uri := range uris {
+				Expect(grobotstxt.AgentAllowed(formatted, agent, uri)).
+					To(Equal(grobotstxt.AgentAllowed(robotstxt, agent, uri)), "%q %s %s", robotstxt, agent, uri)
+			}
+		}
+	}
+
+	It("should not correct typos when AllowFrequentTypos is false", func() {
+		defer func() { grobotstxt.AllowFrequentTypos = true }()
+		grobotstxt.AllowFrequentTypos = false
+		const robotstxt = "User-agent: FooBot\nDisalow: /\n"
+		Expect(grobotstxt.Format(robotstxt)).To(Equal(robotstxt))
+		Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/a")).To(BeTrue())
+	})
+
+	It("should not change the verdicts of oversized files", func() {
+		robotstxt := "User-agent: *\nDisallow: /x\n" +
+			strings.Repeat("\n", 600*1024) +
+			"Disallow: /y\n"
+		formatted := grobotstxt.Format(robotstxt)
+		Expect(formatted).To(Equal("User-agent: *\nDisallow: /x\n\n# Disallow: /y\n"))
+		Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/y")).To(BeTrue())
+		Expect(grobotstxt.AgentAllowed(formatted, "FooBot", "http://foo.bar/y")).To(BeTrue())
+
+		// The cut falls within the last line.
+		head := "User-agent: *\nDisallow: /x\n"
+		pad := strings.Repeat("#", grobotstxt.DefaultMaxBytes-len(head)-len("\nDisallow: /a"))
+		robotstxt = head + pad + "\nDisallow: /abc\n"
+		check(robotstxt)
+		Expect(grobotstxt.Format(robotstxt)).To(Equal(head + pad + "\nDisallow: /a\n\n# bc\n"))
+
+		// Formatting would move the last line past the cut.
+		robotstxt = "User-agent: *\n" + strings.Repeat("Disallow:/a\n", 42000) + "Disallow: /\n"
+		check(robotstxt)
+		Expect(grobotstxt.Format(robotstxt)).To(Equal(robotstxt))
+	})
+
+	It("should not change verdicts", func() {
+		for _, robotstxt := range adversarialRobots {
+			check(robotstxt)
+		}
+		pieces := append([]string{
+			"User-agent: FooBot\n", "User-agent: BarBot\n", "User-agent: *\n",
+			"Disallow: /\n", "Allow: /x/\n", "Disallow: /a\n", "allow /index.html\n",
+			"Sitemap: /s\n", "Crawl-delay: 1\n", "# Comment\n", "\n", "disalow: /s%c3%a4le/\n",
+		}, fragments...)
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			var sb strings.Builder
+			for n := rnd.Intn(30); n > 0; n-- {
+				sb.WriteString(pieces[rnd.Intn(len(pieces))])
+			}
+			check(sb.String())
+		}
+	})
+
+})
@@ -0,0 +1,127 @@
+package grobotstxt_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Size limit", func() {
+
+	// padding returns comment lines totalling exactly n bytes.
+	padding := func(n int) string {
+		var sb strings.Builder
+		for n > 0 {
+			l := 80
+			if n < l {
+				l = n
+			}
+			if l == 1 {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("#" + strings.Repeat("x", l-2) + "\n")
+			}
+			n -= l
+		}
+		return sb.String()
+	}
+
+	const header = "User-agent: *\nDisallow: /x\n"
+
+	It("should ignore rules after 500 KiB", func() {
+		head := header + padding(grobotstxt.DefaultMaxBytes-len(header))
+		Expect(head).To(HaveLen(grobotstxt.DefaultMaxBytes))
+		lines := strings.Count(head, "\n")
+
+		robotstxt := head + "Disallow: /y\n"
+		Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/x")).To(BeFalse())
+		Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/y")).To(BeTrue())
+
+		m := grobotstxt.NewRobotsMatcher()
+		m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/y")
+		line, ok := m.Truncated()
+		Expect(ok).To(BeTrue())
+		Expect(line).To(Equal(lines + 1))
+
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Allowed("FooBot", "http://foo.bar/y")).To(BeTrue())
+		line, ok = robots.Truncated()
+		Expect(ok).To(BeTrue())
+		Expect(line).To(Equal(lines + 1))
+
+		Expect(grobotstxt.Lint(robotstxt)).To(ContainElement(grobotstxt.Diagnostic{
+			Line:     lines + 1,
+			Severity: grobotstxt.Warning,
+			Kind:     grobotstxt.FileTooLarge,
+			Message:  "file is larger than 512000 bytes, and is ignored from here on",
+		}))
+	})
+
+	It("should not truncate a file of exactly the maximum size", func() {
+		robotstxt := header + padding(grobotstxt.DefaultMaxBytes-len(header)-len("Disallow: /y"))
+		robotstxt += "Disallow: /y"
+		Expect(robotstxt).To(HaveLen(grobotstxt.DefaultMaxBytes))
+
+		m := grobotstxt.NewRobotsMatcher()
+		Expect(m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/y")).To(BeFalse())
+		_, ok := m.Truncated()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should parse a partially cut line up to the cut", func() {
+		robotstxt := header + "Disallow: /abcdef\n"
+		m := grobotstxt.NewRobotsMatcher()
+		m.MaxBytes = len(header + "Disallow: /abc")
+		Expect(m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/abcxyz")).To(BeFalse())
+		Expect(m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/abxyz")).To(BeTrue())
+		line, ok := m.Truncated()
+		Expect(ok).To(BeTrue())
+		Expect(line).To(Equal(3))
+
+		// A limit that cuts the line's key leaves a line without a separator.
+		report := &robotsStatsReporter{}
+		p := grobotstxt.NewParser(robotstxt, report)
+		p.MaxBytes = len(header + "Disa")
+		p.Parse()
+		Expect(report.validDirectives).To(Equal(2))
+		Expect(report.unknownDirectives).To(Equal(0))
+		line, ok = p.Truncated()
+		Expect(ok).To(BeTrue())
+		Expect(line).To(Equal(3))
+	})
+
+	It("should count a byte order mark", func() {
+		robotstxt := "\xEF\xBB\xBF" + header
+		m := grobotstxt.NewRobotsMatcher()
+		m.MaxBytes = len(robotstxt) - 1
+		Expect(m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/x")).To(BeFalse())
+		_, ok := m.Truncated()
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should not limit the body if MaxBytes is negative", func() {
+		robotstxt := header + padding(grobotstxt.DefaultMaxBytes) + "Disallow: /y\n"
+		m := grobotstxt.NewRobotsMatcher()
+		m.MaxBytes = -1
+		Expect(m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/y")).To(BeFalse())
+		_, ok := m.Truncated()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should stop reading a stream at the limit", func() {
+		readErr := errors.New("read too far")
+		r := &failingReader{r: strings.NewReader(header + "Disallow: /y\n"), err: readErr}
+		report := &robotsStatsReporter{}
+		Expect(grobotstxt.ParseReader(r, report)).To(Equal(readErr))
+
+		r = &failingReader{r: strings.NewReader(header + "Disallow: /y\n" + padding(grobotstxt.DefaultMaxBytes)), err: readErr}
+		report = &robotsStatsReporter{}
+		Expect(grobotstxt.ParseReader(r, report)).To(Succeed())
+		Expect(report.validDirectives).To(Equal(3))
+	})
+
+})
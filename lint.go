@@ -0,0 +1,224 @@
+package grobotstxt
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity is the severity of a Diagnostic.
+type Severity int
+
+// Severities, in increasing order.
+const (
+	Info    Severity = iota // Info is for lines that are valid, but have no effect.
+	Warning                 // Warning is for lines that are accepted, but may not be read as intended.
+	Error                   // Error is for lines that are ignored.
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// DiagnosticKind classifies a Diagnostic.
+type DiagnosticKind int
+
+// Kinds of Diagnostic.
+const (
+	// EmptyFile is reported for a file without any directives.
+	EmptyFile DiagnosticKind = iota + 1
+	// InvalidLine is reported for a line that is not a comment,
+	// and cannot be split into a key and a value.
+	InvalidLine
+	// MissingColon is reported for a line whose key and value are
+	// separated by whitespace instead of ':'.
+	MissingColon
+	// KeyTypo is reported for a known key that is not spelled in the
+	// standard way, such as "DISALOW" or "useragent".
+	KeyTypo
+	// UnknownDirective is reported for a key that is not recognised.
+	UnknownDirective
+	// RuleBeforeUserAgent is reported for an Allow or Disallow line that
+	// comes before any User-agent line, and is therefore ignored.
+	RuleBeforeUserAgent
+	// LineTooLong is reported for a line that is cut short by the parser.
+	LineTooLong
+	// UserAgentTruncated is reported for a User-agent value that is only
+	// partly matched, such as "Googlebot/2.1", which matches "Googlebot".
+	UserAgentTruncated
+	// FileTooLarge is reported for the line at which the parser stops,
+	// because the file is larger than Parser.MaxBytes.
+	FileTooLarge
+)
+
+// String returns a short name for the kind of diagnostic.
+func (k DiagnosticKind) String() string {
+	switch k {
+	case EmptyFile:
+		return "empty file"
+	case InvalidLine:
+		return "invalid line"
+	case MissingColon:
+		return "missing colon"
+	case KeyTypo:
+		return "key typo"
+	case UnknownDirective:
+		return "unknown directive"
+	case RuleBeforeUserAgent:
+		return "rule before user-agent"
+	case LineTooLong:
+		return "line too long"
+	case UserAgentTruncated:
+		return "user-agent truncated"
+	case FileTooLarge:
+		return "file too large"
+	default:
+		return "unknown diagnostic"
+	}
+}
+
+// Diagnostic reports a problem with a line of a robots.txt file.
+type Diagnostic struct {
+	Line     int // Line number within the robots.txt file, or 0 for the whole file.
+	Severity Severity
+	Kind     DiagnosticKind
+	Message  string
+}
+
+// String returns the diagnostic in the form "line 3: warning: message".
+func (d Diagnostic) String() string {
+	s := d.Severity.String() + ": " + d.Message
+	if d.Line == 0 {
+		return s
+	}
+	return "line " + strconv.Itoa(d.Line) + ": " + s
+}
+
+// Lint parses the given robots.txt content, and returns diagnostics for
+// the problems the parser tolerates: typos, missing colons, unknown
+// directives, rules outside of any group, and so on. The diagnostics are
+// ordered by line. Lint does not change how the file is parsed or matched.
+func Lint(robotsBody string) []Diagnostic {
+	l := &linter{}
+	p := NewParser(robotsBody, l)
+	p.diagnose = l.add
+	p.Parse()
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		return l.diagnostics[i].Line < l.diagnostics[j].Line
+	})
+	return l.diagnostics
+}
+
+// report sends a diagnostic to the Parser's diagnose func, if it has one.
+func (p *Parser) report(lineNum int, severity Severity, kind DiagnosticKind, message string) {
+	if p.diagnose != nil {
+		p.diagnose(Diagnostic{
+			Line:     lineNum,
+			Severity: severity,
+			Kind:     kind,
+			Message:  message,
+		})
+	}
+}
+
+func (p *Parser) reportTruncated(lineNum int, truncated bool) {
+	if truncated {
+		p.report(lineNum, Warning, LineTooLong,
+			"line is longer than "+strconv.Itoa(p.opts.maxLineLen())+" bytes, and was truncated")
+	}
+}
+
+// diagnoseKey reports keys that are unknown, or that are not spelled in the standard way.
+func (p *Parser) diagnoseKey(lineNum int, stringKey string, key parsedKey) {
+	if key.Type() == unknownKey {
+		p.report(lineNum, Info, UnknownDirective, "unknown directive "+strconv.Quote(stringKey)+" is ignored")
+		return
+	}
+	name := keyNames[key.Type()]
+	if key.Type() == customKey {
+		name = key.directive.Name
+	}
+	if !strings.EqualFold(stringKey, name) {
+		p.report(lineNum, Warning, KeyTypo, strconv.Quote(stringKey)+" is read as "+strconv.Quote(name))
+	}
+}
+
+//
+
+// linter is a ParseHandler that reports problems with the structure of a
+// robots.txt file. The Parser itself reports problems with individual lines.
+type linter struct {
+	diagnostics []Diagnostic
+
+	seenAgent     bool // True if any User-agent line has been seen.
+	seenDirective bool // True if any directive has been seen.
+}
+
+func (l *linter) add(d Diagnostic) {
+	l.diagnostics = append(l.diagnostics, d)
+}
+
+func (l *linter) HandleRobotsStart() {
+	l.diagnostics = nil
+	l.seenAgent = false
+	l.seenDirective = false
+}
+
+func (l *linter) HandleRobotsEnd() {
+	if !l.seenDirective {
+		l.add(Diagnostic{
+			Severity: Warning,
+			Kind:     EmptyFile,
+			Message:  "file has no directives, so all user agents are allowed",
+		})
+	}
+}
+
+func (l *linter) HandleUserAgent(lineNum int, value string) {
+	l.seenAgent = true
+	l.seenDirective = true
+	if agent, global := groupAgent(value); !global && agent != value {
+		l.add(Diagnostic{
+			Line:     lineNum,
+			Severity: Warning,
+			Kind:     UserAgentTruncated,
+			Message:  "user-agent " + strconv.Quote(value) + " is matched as " + strconv.Quote(agent),
+		})
+	}
+}
+
+func (l *linter) HandleAllow(lineNum int, value string) {
+	l.handleRule(lineNum)
+}
+
+func (l *linter) HandleDisallow(lineNum int, value string) {
+	l.handleRule(lineNum)
+}
+
+func (l *linter) handleRule(lineNum int) {
+	l.seenDirective = true
+	if !l.seenAgent {
+		l.add(Diagnostic{
+			Line:     lineNum,
+			Severity: Error,
+			Kind:     RuleBeforeUserAgent,
+			Message:  "rule comes before any user-agent, and is ignored",
+		})
+	}
+}
+
+func (l *linter) HandleSitemap(lineNum int, value string) {
+	l.seenDirective = true
+}
+
+func (l *linter) HandleUnknownAction(lineNum int, action, value string) {
+	l.seenDirective = true
+}
@@ -0,0 +1,103 @@
+package grobotstxt_test
+
+import (
+	"strings"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+
+	type result struct {
+		line     int
+		severity grobotstxt.Severity
+		kind     grobotstxt.DiagnosticKind
+	}
+
+	lint := func(robotstxt string) []result {
+		var results []result
+		for _, d := range grobotstxt.Lint(robotstxt) {
+			results = append(results, result{d.Line, d.Severity, d.Kind})
+		}
+		return results
+	}
+
+	It("should report nothing for a clean file", func() {
+		const robotstxt = "# Comment.\n" +
+			"User-agent: FooBot\n" +
+			"Disallow: /\n" +
+			"\n" +
+			"User-agent: *\n" +
+			"Allow: /  # Trailing comment.\n" +
+			"Crawl-delay: 5\n" +
+			"Sitemap: http://foo.bar/sitemap.xml\n"
+		Expect(grobotstxt.Lint(robotstxt)).To(BeEmpty())
+	})
+
+	It("should report empty files", func() {
+		Expect(lint("")).To(Equal([]result{{0, grobotstxt.Warning, grobotstxt.EmptyFile}}))
+		Expect(lint("# Only a comment.\n\n")).To(Equal([]result{{0, grobotstxt.Warning, grobotstxt.EmptyFile}}))
+	})
+
+	It("should report accepted typos and missing colons", func() {
+		const robotstxt = "useragent: FooBot\n" +
+			"DISALOW: /x/\n" +
+			"disallow /y/\n" +
+			"crawl delay: 1\n"
+		Expect(lint(robotstxt)).To(Equal([]result{
+			{1, grobotstxt.Warning, grobotstxt.KeyTypo},
+			{2, grobotstxt.Warning, grobotstxt.KeyTypo},
+			{3, grobotstxt.Warning, grobotstxt.MissingColon},
+			{4, grobotstxt.Warning, grobotstxt.KeyTypo},
+		}))
+	})
+
+	It("should report invalid lines and unknown directives", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Disallow\n" +
+			"Disallow / /x/\n" +
+			": /\n" +
+			"Noindex: /\n"
+		Expect(lint(robotstxt)).To(Equal([]result{
+			{2, grobotstxt.Error, grobotstxt.InvalidLine},
+			{3, grobotstxt.Error, grobotstxt.InvalidLine},
+			{4, grobotstxt.Error, grobotstxt.InvalidLine},
+			{5, grobotstxt.Info, grobotstxt.UnknownDirective},
+		}))
+	})
+
+	It("should report rules before any user-agent", func() {
+		const robotstxt = "Allow: /foo/\n" +
+			"User-agent: *\n" +
+			"Disallow: /\n"
+		Expect(lint(robotstxt)).To(Equal([]result{{1, grobotstxt.Error, grobotstxt.RuleBeforeUserAgent}}))
+	})
+
+	It("should report truncated user-agents", func() {
+		const robotstxt = "User-agent: Googlebot/2.1\n" +
+			"User-agent: * ignored\n" +
+			"Disallow: /\n"
+		diagnostics := grobotstxt.Lint(robotstxt)
+		Expect(diagnostics).To(HaveLen(1))
+		Expect(diagnostics[0].Kind).To(Equal(grobotstxt.UserAgentTruncated))
+		Expect(diagnostics[0].String()).To(Equal(`line 1: warning: user-agent "Googlebot/2.1" is matched as "Googlebot"`))
+
+		diagnostics = grobotstxt.Lint("User-agent: */1.0\nDisallow: /\n")
+		Expect(diagnostics).To(HaveLen(1))
+		Expect(diagnostics[0].String()).To(Equal(`line 1: warning: user-agent "*/1.0" is matched as "*"`))
+		Expect(grobotstxt.Lint("User-agent: * FooBot\nDisallow: /\n")).To(BeEmpty())
+	})
+
+	It("should report truncated lines", func() {
+		robotstxt := "User-agent: *\n" +
+			"Disallow: /" + strings.Repeat("a", 20000) + "\n" +
+			"Disallow: /" + strings.Repeat("b", 20000)
+		Expect(lint(robotstxt)).To(Equal([]result{
+			{2, grobotstxt.Warning, grobotstxt.LineTooLong},
+			{3, grobotstxt.Warning, grobotstxt.LineTooLong},
+		}))
+	})
+
+})
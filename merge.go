@@ -0,0 +1,189 @@
+package grobotstxt
+
+// MergeMode selects how a Policy combines its sources.
+type MergeMode int
+
+// Merge modes.
+const (
+	// MostRestrictive allows a URI only if every source allows it.
+	MostRestrictive MergeMode = iota
+	// FirstSource uses, for each set of user agents, the first source with
+	// a group naming one of them, or else the first source with a global
+	// '*' group. If no source has any such group, everything is allowed.
+	FirstSource
+	// UnionGroups combines the groups of all sources, in order, as if the
+	// sources were one robots.txt file.
+	UnionGroups
+)
+
+// String returns the name of the merge mode.
+func (m MergeMode) String() string {
+	switch m {
+	case MostRestrictive:
+		return "most restrictive"
+	case FirstSource:
+		return "first source"
+	case UnionGroups:
+		return "union of groups"
+	default:
+		return "unknown merge mode"
+	}
+}
+
+// Source is a named robots.txt policy, to be merged with others.
+// The name is used to explain which source a rule came from,
+// e.g. "cdn", "origin" or "overrides".
+type Source struct {
+	Name   string
+	Robots *Robots
+}
+
+// NewSource compiles the given robots.txt content into a named Source.
+func NewSource(name, robotsBody string) (Source, error) {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return Source{}, err
+	}
+	return Source{Name: name, Robots: r}, nil
+}
+
+// Policy is the effective policy of several robots.txt sources, combined
+// using a MergeMode. Like Robots, a Policy is immutable, and is safe for
+// concurrent use by multiple goroutines.
+type Policy struct {
+	mode    MergeMode
+	sources []*Robots // Copies of the sources' Robots, with provenance.
+	names   []string
+	union   *Robots // All groups, for UnionGroups.
+}
+
+// Merge combines the given sources into a Policy, using the given mode.
+// Sources earlier in the list come first.
+func Merge(mode MergeMode, sources ...Source) *Policy {
+	p := &Policy{
+		mode: mode,
+		union: &Robots{
+			profile: GoogleProfile,
+		},
+	}
+	for i, s := range sources {
+		if i == 0 {
+			p.union.profile = s.Robots.profile
+		}
+		r := *s.Robots
+		r.groups = make([]Group, len(s.Robots.groups))
+		for i, g := range s.Robots.groups {
+			g.source = s.Name
+			r.groups[i] = g
+		}
+		r.compilePatterns()
+		p.sources = append(p.sources, &r)
+		p.names = append(p.names, s.Name)
+
+		p.union.groups = append(p.union.groups, r.groups...)
+		p.union.sitemaps = append(p.union.sitemaps, r.sitemaps...)
+		p.union.hosts = append(p.union.hosts, r.hosts...)
+		p.union.cleanParams = append(p.union.cleanParams, r.cleanParams...)
+	}
+	p.union.compilePatterns()
+	return p
+}
+
+// Mode returns the Policy's merge mode.
+func (p *Policy) Mode() MergeMode {
+	return p.mode
+}
+
+// Sitemaps returns the "Sitemap:" values of all sources, in order.
+func (p *Policy) Sitemaps() []string {
+	return p.union.sitemaps
+}
+
+// AgentsAllowed returns true if the given URI is allowed to be
+// fetched by any user agent in the list, under the Policy's merge mode.
+//
+// AgentsAllowed will also return false if the given URI is invalid
+// (cannot successfully be parsed by url.Parse).
+func (p *Policy) AgentsAllowed(userAgents []string, uri string) bool {
+	switch p.mode {
+	case MostRestrictive:
+		if len(p.sources) > 0 {
+			for _, r := range p.sources {
+				if !r.AgentsAllowed(userAgents, uri) {
+					return false
+				}
+			}
+			return true
+		}
+	case FirstSource:
+		if r := p.firstSource(userAgents); r != nil {
+			return r.AgentsAllowed(userAgents, uri)
+		}
+	}
+	return p.union.AgentsAllowed(userAgents, uri)
+}
+
+// Allowed returns true if the given URI is allowed to be fetched
+// by the given user agent, under the Policy's merge mode.
+//
+// Allowed will also return false if the given URI is invalid
+// (cannot successfully be parsed by url.Parse).
+func (p *Policy) Allowed(userAgent string, uri string) bool {
+	return p.AgentsAllowed([]string{userAgent}, uri)
+}
+
+// Explain returns the decision for the given userAgents and URI, explaining
+// the verdict given by AgentsAllowed. The rules of the decision name the
+// source they came from.
+//
+// For MostRestrictive, the decision is that of the first source that
+// disallows the URI, or else of the first source with a matching rule.
+func (p *Policy) Explain(userAgents []string, uri string) Decision {
+	switch p.mode {
+	case MostRestrictive:
+		var first *Decision
+		for i, r := range p.sources {
+			d := r.Explain(userAgents, uri)
+			if !d.Allowed {
+				return p.fromSource(i, d)
+			}
+			if first == nil && d.Winner != nil {
+				d = p.fromSource(i, d)
+				first = &d
+			}
+		}
+		if first != nil {
+			return *first
+		}
+	case FirstSource:
+		first := p.firstSource(userAgents)
+		for i, r := range p.sources {
+			if r == first {
+				return p.fromSource(i, r.Explain(userAgents, uri))
+			}
+		}
+	}
+	return p.union.Explain(userAgents, uri)
+}
+
+// fromSource adds the name of the i'th source to the reason of the decision.
+func (p *Policy) fromSource(i int, d Decision) Decision {
+	d.Reason = "according to " + p.names[i] + ", " + d.Reason
+	return d
+}
+
+// firstSource returns the first source with a group naming one of the
+// given userAgents, or else the first with a global group, or nil.
+func (p *Policy) firstSource(userAgents []string) *Robots {
+	var global *Robots
+	for _, r := range p.sources {
+		specific, globals := r.selectGroups(userAgents)
+		if len(specific) > 0 {
+			return r
+		}
+		if global == nil && len(globals) > 0 {
+			global = r
+		}
+	}
+	return global
+}
@@ -0,0 +1,91 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merge", func() {
+
+	const cdn = "User-agent: *\n" +
+		"Disallow: /cdn-cgi/\n" +
+		"Sitemap: http://foo.bar/cdn.xml\n"
+
+	const origin = "User-agent: FooBot\n" +
+		"Disallow: /private/\n" +
+		"\n" +
+		"User-agent: *\n" +
+		"Allow: /cdn-cgi/status\n" +
+		"Disallow: /tmp/\n" +
+		"Sitemap: http://foo.bar/sitemap.xml\n"
+
+	const overrides = "User-agent: *\n" +
+		"Disallow: /admin/\n"
+
+	sources := func() []grobotstxt.Source {
+		var sources []grobotstxt.Source
+		for _, s := range [][2]string{{"cdn", cdn}, {"origin", origin}, {"overrides", overrides}} {
+			source, err := grobotstxt.NewSource(s[0], s[1])
+			Expect(err).NotTo(HaveOccurred())
+			sources = append(sources, source)
+		}
+		return sources
+	}
+
+	type verdicts map[string]bool
+
+	check := func(p *grobotstxt.Policy, agent string, expected verdicts) {
+		for uri, allowed := range expected {
+			Expect(p.Allowed(agent, "http://foo.bar"+uri)).To(Equal(allowed), agent+" "+uri)
+			Expect(p.Explain([]string{agent}, "http://foo.bar"+uri).Allowed).To(Equal(allowed), agent+" "+uri)
+		}
+	}
+
+	It("should allow only what every source allows", func() {
+		p := grobotstxt.Merge(grobotstxt.MostRestrictive, sources()...)
+		Expect(p.Mode()).To(Equal(grobotstxt.MostRestrictive))
+		check(p, "BarBot", verdicts{"/": true, "/cdn-cgi/status": false, "/tmp/": false, "/admin/": false})
+		check(p, "FooBot", verdicts{"/": true, "/cdn-cgi/x": false, "/tmp/": true, "/private/": false, "/admin/": false})
+
+		d := p.Explain([]string{"BarBot"}, "http://foo.bar/admin/")
+		Expect(d.Winner.Source).To(Equal("overrides"))
+		Expect(d.Reason).To(HavePrefix(`according to overrides, in the global '*' groups, Disallow "/admin/" (line 2 of overrides)`))
+	})
+
+	It("should use the first source with a group for the agent", func() {
+		p := grobotstxt.Merge(grobotstxt.FirstSource, sources()...)
+		// The CDN's global group comes first.
+		check(p, "BarBot", verdicts{"/": true, "/cdn-cgi/status": false, "/tmp/": true, "/admin/": true})
+		// The origin is the first source naming FooBot.
+		check(p, "FooBot", verdicts{"/cdn-cgi/x": true, "/private/": false, "/admin/": true})
+		Expect(p.Explain([]string{"FooBot"}, "http://foo.bar/private/").Winner.Source).To(Equal("origin"))
+	})
+
+	It("should combine the groups of all sources", func() {
+		p := grobotstxt.Merge(grobotstxt.UnionGroups, sources()...)
+		check(p, "BarBot", verdicts{"/": true, "/cdn-cgi/x": false, "/cdn-cgi/status": true, "/tmp/": false, "/admin/": false})
+		check(p, "FooBot", verdicts{"/cdn-cgi/x": true, "/private/": false, "/admin/": true})
+
+		d := p.Explain([]string{"BarBot"}, "http://foo.bar/cdn-cgi/status")
+		Expect(d.Winner.Source).To(Equal("origin"))
+		Expect(d.Loser.Source).To(Equal("cdn"))
+		Expect(p.Sitemaps()).To(Equal([]string{"http://foo.bar/cdn.xml", "http://foo.bar/sitemap.xml"}))
+	})
+
+	It("should allow everything without sources", func() {
+		for _, mode := range []grobotstxt.MergeMode{grobotstxt.MostRestrictive, grobotstxt.FirstSource, grobotstxt.UnionGroups} {
+			p := grobotstxt.Merge(mode)
+			Expect(p.Allowed("FooBot", "http://foo.bar/")).To(BeTrue())
+			Expect(p.Allowed("FooBot", "http://foo.bar/%zz")).To(BeFalse())
+		}
+	})
+
+	It("should not change its sources", func() {
+		s := sources()
+		grobotstxt.Merge(grobotstxt.UnionGroups, s...)
+		d := s[0].Robots.Explain([]string{"BarBot"}, "http://foo.bar/cdn-cgi/")
+		Expect(d.Winner.Source).To(BeEmpty())
+	})
+
+})
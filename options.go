@@ -0,0 +1,120 @@
+package grobotstxt
+
+// Options controls which of Google's extensions to RFC 9309 are used by
+// a Parser or RobotsMatcher. Each Parser or RobotsMatcher has its own
+// Options, so parsers with different options can be used concurrently.
+// See NewParser and NewRobotsMatcher.
+//
+// The zero value accepts only what RFC 9309 describes, apart from the
+// product tokens of User-agent values, see Strict, and has the default
+// line length.
+type Options struct {
+	// Typos accepts common misspellings of keys, such as "useragent"
+	// and "disalow".
+	Typos bool
+
+	// KeyPrefixes accepts keys that start with a known key, or with one
+	// of its misspellings if Typos is true, such as "disallowed".
+	// Otherwise keys must be spelled exactly, ignoring case.
+	KeyPrefixes bool
+
+	// MissingColons accepts lines with whitespace between their key and
+	// value, instead of a ':', such as "Disallow /".
+	MissingColons bool
+
+	// StarAgentPrefix treats User-agent values that are a '*' followed by
+	// whitespace and more text, such as "* FooBot", as the global agent.
+	// Used by RobotsMatcher.
+	StarAgentPrefix bool
+
+	// NormaliseIndex makes Allow patterns ending in 'index.htm' or
+	// 'index.html' also allow the directory itself. Used by RobotsMatcher.
+	NormaliseIndex bool
+
+	// SkipBOM skips a UTF-8 byte order mark, even a partial one, at the
+	// start of the robots.txt body.
+	SkipBOM bool
+
+	// MaxLineLen is the number of bytes of each line that are parsed.
+	// The rest of a longer line is ignored. Zero means DefaultMaxLineLen,
+	// and a negative value means no limit.
+	MaxLineLen int
+
+	// Directives, if non-nil, declares custom directives to be parsed,
+	// in addition to the standard ones.
+	Directives *Directives
+}
+
+// DefaultMaxLineLen is the default value of Options.MaxLineLen.
+const DefaultMaxLineLen = maxLineLen - 1
+
+// DefaultOptions returns the options of Google's parser and matcher,
+// which are used unless others are given.
+func DefaultOptions() Options {
+	return Options{
+		Typos:           true,
+		KeyPrefixes:     true,
+		MissingColons:   true,
+		StarAgentPrefix: true,
+		NormaliseIndex:  true,
+		SkipBOM:         true,
+	}
+}
+
+// StrictOptions returns the options of strict RFC 9309 parsing.
+// Setting Parser.Strict also checks the values of directives.
+func StrictOptions() Options {
+	return Options{
+		SkipBOM: true,
+	}
+}
+
+// optionsOf returns the first of the given options, or else DefaultOptions.
+func optionsOf(opts []Options) Options {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	o := DefaultOptions()
+	o.Typos = AllowFrequentTypos
+	return o
+}
+
+// options returns the parser's options, as restricted by Strict.
+func (p *Parser) options() Options {
+	return restrict(p.opts, p.Strict)
+}
+
+// options returns the matcher's options, as restricted by Strict.
+func (m *RobotsMatcher) options() Options {
+	return restrict(m.opts, m.Strict)
+}
+
+// restrict turns off the extensions that strict RFC 9309 parsing does
+// not have, if strict is true. The BOM and line length are unchanged.
+func restrict(o Options, strict bool) Options {
+	if strict {
+		o.Typos = false
+		o.KeyPrefixes = false
+		o.MissingColons = false
+		o.StarAgentPrefix = false
+		o.NormaliseIndex = false
+	}
+	return o
+}
+
+// keys returns how keys are matched with the options.
+func (o *Options) keys() keyMatch {
+	return keyMatch{typos: o.Typos, prefixes: o.KeyPrefixes}
+}
+
+// maxLineLen returns the number of bytes of each line that are parsed,
+// or -1 for no limit.
+func (o *Options) maxLineLen() int {
+	if o.MaxLineLen == 0 {
+		return DefaultMaxLineLen
+	}
+	if o.MaxLineLen < 0 {
+		return -1
+	}
+	return o.MaxLineLen
+}
@@ -0,0 +1,131 @@
+package grobotstxt_test
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Options", func() {
+
+	// directives parses the given robots.txt content with the given options,
+	// and returns the number of valid and unknown directives.
+	directives := func(robotstxt string, opts grobotstxt.Options) (valid, unknown int) {
+		report := &robotsStatsReporter{}
+		grobotstxt.NewParser(robotstxt, report, opts).Parse()
+		return report.validDirectives, report.unknownDirectives
+	}
+
+	allowed := func(robotstxt string, opts grobotstxt.Options, uri string) bool {
+		return grobotstxt.NewRobotsMatcher(opts).AgentAllowed(robotstxt, "FooBot", uri)
+	}
+
+	It("should control each extension independently", func() {
+		all := grobotstxt.DefaultOptions()
+		without := func(f func(o *grobotstxt.Options)) grobotstxt.Options {
+			o := all
+			f(&o)
+			return o
+		}
+
+		// Typos.
+		const typos = "useragent: FooBot\ndisalow: /\ndisallowed: /\n"
+		Expect(directives(typos, all)).To(Equal(3))
+		valid, unknown := directives(typos, without(func(o *grobotstxt.Options) { o.Typos = false }))
+		Expect(valid).To(Equal(1))
+		Expect(unknown).To(Equal(2))
+
+		// Keys that start with a known key.
+		valid, unknown = directives(typos, without(func(o *grobotstxt.Options) { o.KeyPrefixes = false }))
+		Expect(valid).To(Equal(2))
+		Expect(unknown).To(Equal(1))
+
+		// Missing colons.
+		const colonless = "user-agent FooBot\ndisallow /\n"
+		valid, _ = directives(colonless, all)
+		Expect(valid).To(Equal(2))
+		valid, _ = directives(colonless, without(func(o *grobotstxt.Options) { o.MissingColons = false }))
+		Expect(valid).To(Equal(0))
+
+		// Global agent followed by other text.
+		const star = "user-agent: * BarBot\ndisallow: /\n"
+		Expect(allowed(star, all, "http://foo.bar/x")).To(BeFalse())
+		Expect(allowed(star, without(func(o *grobotstxt.Options) { o.StarAgentPrefix = false }), "http://foo.bar/x")).To(BeTrue())
+
+		// 'index.html' normalisation.
+		const index = "user-agent: FooBot\ndisallow: /\nallow: /a/index.html\n"
+		Expect(allowed(index, all, "http://foo.bar/a/")).To(BeTrue())
+		Expect(allowed(index, without(func(o *grobotstxt.Options) { o.NormaliseIndex = false }), "http://foo.bar/a/")).To(BeFalse())
+
+		// BOM.
+		const bom = "\xEF\xBB\xBFuser-agent: FooBot\ndisallow: /\n"
+		valid, _ = directives(bom, all)
+		Expect(valid).To(Equal(2))
+		valid, unknown = directives(bom, without(func(o *grobotstxt.Options) { o.SkipBOM = false }))
+		Expect(valid).To(Equal(1))
+		Expect(unknown).To(Equal(1))
+	})
+
+	It("should limit line length", func() {
+		long := "user-agent: FooBot\ndisallow: /" + strings.Repeat("a", grobotstxt.DefaultMaxLineLen) + "b\n"
+		uri := "http://foo.bar/" + strings.Repeat("a", grobotstxt.DefaultMaxLineLen) + "c"
+
+		// The truncated pattern matches more.
+		Expect(allowed(long, grobotstxt.DefaultOptions(), uri)).To(BeFalse())
+
+		opts := grobotstxt.DefaultOptions()
+		opts.MaxLineLen = -1
+		Expect(allowed(long, opts, uri)).To(BeTrue())
+
+		opts.MaxLineLen = len("user-agent: FooBot")
+		Expect(allowed(long, opts, "http://foo.bar/aaaaaaaz")).To(BeFalse())
+		Expect(allowed(long, opts, "http://foo.bar/aaaaaaz")).To(BeTrue())
+	})
+
+	It("should default to Google's extensions", func() {
+		const robotstxt = "useragent FooBot\ndisalow: /a/\n"
+		Expect(grobotstxt.NewRobotsMatcher().AgentAllowed(robotstxt, "FooBot", "http://foo.bar/a/")).To(BeFalse())
+		Expect(allowed(robotstxt, grobotstxt.DefaultOptions(), "http://foo.bar/a/")).To(BeFalse())
+		Expect(allowed(robotstxt, grobotstxt.StrictOptions(), "http://foo.bar/a/")).To(BeTrue())
+		Expect(allowed(robotstxt, grobotstxt.Options{}, "http://foo.bar/a/")).To(BeTrue())
+	})
+
+	It("should keep key prefixes when AllowFrequentTypos is false", func() {
+		defer func() { grobotstxt.AllowFrequentTypos = true }()
+		grobotstxt.AllowFrequentTypos = false
+
+		const robotstxt = "User-agent: *\nDisallowed: /x\nDisalow: /y\n"
+		Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/x")).To(BeFalse())
+		Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/y")).To(BeTrue())
+	})
+
+	It("should be restricted by Strict", func() {
+		const robotstxt = "useragent: FooBot\ndisallow: /\n"
+		m := grobotstxt.NewRobotsMatcher(grobotstxt.DefaultOptions())
+		Expect(m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/")).To(BeFalse())
+		m.Strict = true
+		Expect(m.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/")).To(BeTrue())
+	})
+
+	It("should let parsers with different options run concurrently", func() {
+		const robotstxt = "user-agent: FooBot\ndisalow: /\n"
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			opts := grobotstxt.DefaultOptions()
+			opts.Typos = i%2 == 0
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					Expect(allowed(robotstxt, opts, "http://foo.bar/")).To(Equal(!opts.Typos))
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+})
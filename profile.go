@@ -0,0 +1,231 @@
+package grobotstxt
+
+import "strings"
+
+// GroupSelection is how a Profile chooses the user-agent groups
+// that apply to a crawler.
+type GroupSelection int
+
+// Ways of choosing groups.
+const (
+	// AllMatchingGroups uses every group naming one of the crawler's
+	// user agents, merged together, or else every global '*' group.
+	// This is what Google and RFC 9309 do.
+	AllMatchingGroups GroupSelection = iota
+	// FirstMatchingGroup uses only the first group naming one of the
+	// crawler's user agents, or else only the first global '*' group,
+	// as in the original 1996 robots.txt draft.
+	FirstMatchingGroup
+	// LongestAgentPrefix uses the groups whose user agent is the longest
+	// case-insensitive prefix of one of the crawler's user agents, or else
+	// every global '*' group. For example, for "YandexBot", a group for
+	// "YandexBot" is used instead of a group for "Yandex". This is what
+	// Yandex does.
+	LongestAgentPrefix
+)
+
+// RuleSelection is how a Profile chooses between the Allow and Disallow
+// rules that match a URI.
+type RuleSelection int
+
+// Ways of choosing rules.
+const (
+	// LongestMatch uses the rule with the highest match priority, which for
+	// LongestMatchStrategy is the longest pattern. If an Allow and a Disallow
+	// rule have the same priority, the Allow rule wins. This is what Google
+	// and RFC 9309 do.
+	LongestMatch RuleSelection = iota
+	// LongestMatchDisallowWins is like LongestMatch, except that the
+	// Disallow rule wins a tie.
+	LongestMatchDisallowWins
+	// FirstMatch uses the first rule that matches, in the order of the
+	// robots.txt file, as in the original 1996 robots.txt draft.
+	FirstMatch
+)
+
+// Profile describes how a crawler interprets robots.txt files. Profiles
+// allow the same file to be matched as different crawlers would see it.
+// See CompileProfile.
+type Profile struct {
+	Name string
+
+	Groups GroupSelection
+	Rules  RuleSelection
+
+	// MatchStrategy gives the match priority of each pattern. Use
+	// PrefixMatchStrategy for crawlers that do not support the '*'
+	// and '$' wildcards.
+	MatchStrategy MatchStrategy
+
+	// Typos is true if the crawler accepts common misspellings of keys,
+	// such as "useragent" and "disalow". Otherwise keys must be spelled
+	// correctly, ignoring case.
+	Typos bool
+
+	// NormaliseIndex is true if Allow patterns ending in 'index.htm'
+	// or 'index.html' also allow the directory itself, as Google does.
+	NormaliseIndex bool
+}
+
+// Interpretation profiles of major crawlers. Apart from GoogleProfile,
+// which is how this package has always matched, these follow each
+// crawler's published documentation, which does not cover every case.
+var (
+	// GoogleProfile is how Googlebot interprets robots.txt files.
+	GoogleProfile = Profile{
+		Name:           "Google",
+		Groups:         AllMatchingGroups,
+		Rules:          LongestMatch,
+		MatchStrategy:  LongestMatchStrategy{},
+		Typos:          true,
+		NormaliseIndex: true,
+	}
+
+	// BingProfile is how Bingbot interprets robots.txt files:
+	// as RFC 9309 describes, without Google's extensions.
+	BingProfile = Profile{
+		Name:          "Bing",
+		Groups:        AllMatchingGroups,
+		Rules:         LongestMatch,
+		MatchStrategy: LongestMatchStrategy{},
+	}
+
+	// YandexProfile is how Yandex's crawlers interpret robots.txt files.
+	YandexProfile = Profile{
+		Name:          "Yandex",
+		Groups:        LongestAgentPrefix,
+		Rules:         LongestMatch,
+		MatchStrategy: LongestMatchStrategy{},
+	}
+
+	// DraftProfile is how the original 1996 robots.txt draft interprets
+	// robots.txt files: the first matching group and the first matching
+	// rule are used, and patterns are plain prefixes, without wildcards.
+	DraftProfile = Profile{
+		Name:          "Draft",
+		Groups:        FirstMatchingGroup,
+		Rules:         FirstMatch,
+		MatchStrategy: PrefixMatchStrategy{},
+	}
+)
+
+// Profiles holds the predefined profiles, for comparing how each of
+// them sees the same robots.txt file.
+var Profiles = []Profile{GoogleProfile, BingProfile, YandexProfile, DraftProfile}
+
+// CompileProfile is like Compile, but the Robots interprets the
+// robots.txt content as the given profile does.
+func CompileProfile(robotsBody string, profile Profile) (*Robots, error) {
+	opts := optionsOf(nil)
+	if !profile.Typos {
+		opts.Typos = false
+		opts.KeyPrefixes = false
+	}
+	return compile(NewParser(robotsBody, nil, opts), profile)
+}
+
+// Profile returns the profile used to interpret the robots.txt file.
+func (r *Robots) Profile() Profile {
+	return r.profile
+}
+
+//
+
+var _ MatchStrategy = PrefixMatchStrategy{}
+
+// PrefixMatchStrategy matches patterns as plain prefixes of the path,
+// where '*' and '$' have no special meaning.
+//
+// The number of characters in a matching pattern is returned as its match priority.
+type PrefixMatchStrategy struct{}
+
+func (s PrefixMatchStrategy) MatchAllow(path, pattern string) int {
+	return prefixMatch(path, pattern)
+}
+
+func (s PrefixMatchStrategy) MatchDisallow(path, pattern string) int {
+	return prefixMatch(path, pattern)
+}
+
+func prefixMatch(path, pattern string) int {
+	if strings.HasPrefix(path, pattern) {
+		return len(pattern)
+	}
+	return -1
+}
+
+//
+
+// selectGroups returns the groups that apply to the given user agents,
+// and the global groups that apply if there are none, in the order they
+// appear, as chosen by the profile.
+func (r *Robots) selectGroups(userAgents []string) (specific, global []*Group) {
+	longest := 0
+	for i := range r.groups {
+		g := &r.groups[i]
+		switch r.profile.Groups {
+		case LongestAgentPrefix:
+			if n := g.agentPrefixLen(userAgents); n > longest {
+				longest = n
+				specific = []*Group{g}
+			} else if n > 0 && n == longest {
+				specific = append(specific, g)
+			} else if n == 0 && g.isGlobal() {
+				global = append(global, g)
+			}
+		case FirstMatchingGroup:
+			if g.isSpecific(userAgents) {
+				if len(specific) == 0 {
+					specific = append(specific, g)
+				}
+			} else if g.isGlobal() && len(global) == 0 {
+				global = append(global, g)
+			}
+		default:
+			if g.isSpecific(userAgents) {
+				specific = append(specific, g)
+			} else if g.isGlobal() {
+				global = append(global, g)
+			}
+		}
+	}
+	return specific, global
+}
+
+// agentPrefixLen returns the length of the group's longest user agent that
+// is a case-insensitive prefix of one of the given userAgents, or 0 if none is.
+func (g *Group) agentPrefixLen(userAgents []string) int {
+	longest := 0
+	for _, agent := range g.Agents {
+		if !g.isToken(agent) || len(agent) <= longest {
+			continue
+		}
+		for _, userAgent := range userAgents {
+			if startsWithIgnoreCase(userAgent, agent) {
+				longest = len(agent)
+				break
+			}
+		}
+	}
+	return longest
+}
+
+// resolve applies the profile's rule selection to the best Allow and
+// Disallow matches of a group, clearing the one that loses, if any.
+func (p *Profile) resolve(allow, disallow *match) {
+	if allow.priority <= 0 || disallow.priority <= 0 {
+		return
+	}
+	switch p.Rules {
+	case LongestMatchDisallowWins:
+		if allow.priority == disallow.priority {
+			allow.Clear()
+		}
+	case FirstMatch:
+		if allow.line < disallow.line {
+			disallow.Clear()
+		} else {
+			allow.Clear()
+		}
+	}
+}
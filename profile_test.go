@@ -0,0 +1,156 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profile", func() {
+
+	// verdicts returns the verdict of each predefined profile, by name.
+	verdicts := func(robotstxt, userAgent, uri string) map[string]bool {
+		m := make(map[string]bool)
+		for _, profile := range grobotstxt.Profiles {
+			r, err := grobotstxt.CompileProfile(robotstxt, profile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Profile().Name).To(Equal(profile.Name))
+			m[profile.Name] = r.Allowed(userAgent, uri)
+		}
+		return m
+	}
+
+	It("should match Compile with the Google profile", func() {
+		for _, robotstxt := range adversarialRobots {
+			r, err := grobotstxt.CompileProfile(robotstxt, grobotstxt.GoogleProfile)
+			Expect(err).NotTo(HaveOccurred())
+			for _, uri := range []string{"http://foo.bar/", "http://foo.bar/x/y", "http://foo.bar/index.html"} {
+				for _, agent := range []string{"FooBot", "BarBot"} {
+					Expect(r.Allowed(agent, uri)).To(Equal(grobotstxt.AgentAllowed(robotstxt, agent, uri)), "%q", robotstxt)
+				}
+			}
+		}
+	})
+
+	It("should use the first matching rule for the draft", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Allow: /a\n" +
+			"Disallow: /a/b\n" +
+			"Disallow: /c\n" +
+			"Allow: /c/d\n"
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/a/b")).To(Equal(map[string]bool{
+			"Google": false, "Bing": false, "Yandex": false, "Draft": true,
+		}))
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/c/d")).To(Equal(map[string]bool{
+			"Google": true, "Bing": true, "Yandex": true, "Draft": false,
+		}))
+
+		r, err := grobotstxt.CompileProfile(robotstxt, grobotstxt.DraftProfile)
+		Expect(err).NotTo(HaveOccurred())
+		d := r.Explain([]string{"FooBot"}, "http://foo.bar/a/b")
+		Expect(d.Allowed).To(BeTrue())
+		Expect(d.Line()).To(Equal(2))
+		Expect(d.Reason).To(Equal(`in the global '*' groups, Allow "/a" (line 2) is the first rule to match the path, before Disallow "/a/b" (line 3), so the URI is allowed`))
+	})
+
+	It("should ignore empty patterns for the draft", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Disallow:\n" +
+			"Disallow: /a\n"
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/a")["Draft"]).To(BeFalse())
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/b")["Draft"]).To(BeTrue())
+	})
+
+	It("should not support wildcards for the draft", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Disallow: /*.gif$\n"
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/x.gif")).To(Equal(map[string]bool{
+			"Google": false, "Bing": false, "Yandex": false, "Draft": true,
+		}))
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/*.gif$")["Draft"]).To(BeFalse())
+	})
+
+	It("should use the first matching group for the draft", func() {
+		const robotstxt = "User-agent: FooBot\n" +
+			"Disallow: /a\n" +
+			"\n" +
+			"User-agent: *\n" +
+			"Disallow: /c\n" +
+			"\n" +
+			"User-agent: FooBot\n" +
+			"User-agent: *\n" +
+			"Disallow: /b\n"
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/b")).To(Equal(map[string]bool{
+			"Google": false, "Bing": false, "Yandex": false, "Draft": true,
+		}))
+		Expect(verdicts(robotstxt, "BarBot", "http://foo.bar/b")).To(Equal(map[string]bool{
+			"Google": false, "Bing": false, "Yandex": false, "Draft": true,
+		}))
+		Expect(verdicts(robotstxt, "BarBot", "http://foo.bar/c")["Draft"]).To(BeFalse())
+	})
+
+	It("should use the longest agent prefix for Yandex", func() {
+		robotstxt := "User-agent: *\n" +
+			"Allow: /\n" +
+			"\n" +
+			"User-agent: Yandex\n" +
+			"Disallow: /\n"
+		Expect(verdicts(robotstxt, "YandexBot", "http://foo.bar/a")).To(Equal(map[string]bool{
+			"Google": true, "Bing": true, "Yandex": false, "Draft": true,
+		}))
+
+		robotstxt += "\n" +
+			"User-agent: YandexBot\n" +
+			"Disallow: /private/\n" +
+			"\n" +
+			"User-agent: YANDEXBOT\n" +
+			"Disallow: /secret/\n"
+		for path, allowed := range map[string]bool{"/a": true, "/private/a": false, "/secret/a": false} {
+			Expect(verdicts(robotstxt, "YandexBot", "http://foo.bar"+path)["Yandex"]).To(Equal(allowed), path)
+		}
+		Expect(verdicts(robotstxt, "YandexImages", "http://foo.bar/a")["Yandex"]).To(BeFalse())
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/a")["Yandex"]).To(BeTrue())
+	})
+
+	It("should only accept typos for Google", func() {
+		const robotstxt = "User-agent: FooBot\n" +
+			"Disalow: /a\n" +
+			"Disallow: /b\n"
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/a")).To(Equal(map[string]bool{
+			"Google": false, "Bing": true, "Yandex": true, "Draft": true,
+		}))
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/b")).To(Equal(map[string]bool{
+			"Google": false, "Bing": false, "Yandex": false, "Draft": false,
+		}))
+	})
+
+	It("should only normalise index.html for Google", func() {
+		const robotstxt = "User-agent: FooBot\n" +
+			"Disallow: /\n" +
+			"Allow: /a/index.html\n"
+		Expect(verdicts(robotstxt, "FooBot", "http://foo.bar/a/")).To(Equal(map[string]bool{
+			"Google": true, "Bing": false, "Yandex": false, "Draft": false,
+		}))
+	})
+
+	It("should let Disallow win ties", func() {
+		const robotstxt = "User-agent: FooBot\n" +
+			"Allow: /a\n" +
+			"Disallow: /a\n" +
+			"Allow: /b\n"
+		profile := grobotstxt.BingProfile
+		profile.Name = "Strict"
+		profile.Rules = grobotstxt.LongestMatchDisallowWins
+		r, err := grobotstxt.CompileProfile(robotstxt, profile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Allowed("FooBot", "http://foo.bar/a")).To(BeFalse())
+		Expect(r.Allowed("FooBot", "http://foo.bar/b")).To(BeTrue())
+		Expect(grobotstxt.AgentAllowed(robotstxt, "FooBot", "http://foo.bar/a")).To(BeTrue())
+
+		d := r.Explain([]string{"FooBot"}, "http://foo.bar/a")
+		Expect(d.Allowed).To(BeFalse())
+		Expect(d.Line()).To(Equal(3))
+		Expect(d.Reason).To(Equal(`in the groups for FooBot, Disallow "/a" (line 3) and Allow "/a" (line 2) match the path with equal priority 2, so the Disallow wins, and the URI is disallowed`))
+	})
+
+})
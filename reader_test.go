@@ -0,0 +1,156 @@
+package grobotstxt_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing/iotest"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// failingReader returns its content, followed by an error.
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		err = f.err
+	}
+	return n, err
+}
+
+var _ = Describe("Bytes and Reader", func() {
+
+	// Parses the given content as a string, a byte slice, and
+	// a stream, and checks that the reports agree.
+	parse := func(robotstxt string) *robotsStatsReporter {
+		report := &robotsStatsReporter{}
+		grobotstxt.Parse(robotstxt, report)
+
+		bytesReport := &robotsStatsReporter{}
+		grobotstxt.ParseBytes([]byte(robotstxt), bytesReport)
+		Expect(bytesReport).To(Equal(report))
+
+		readerReport := &robotsStatsReporter{}
+		// OneByteReader hides strings.Reader's ReadByte method.
+		err := grobotstxt.ParseReader(iotest.OneByteReader(strings.NewReader(robotstxt)), readerReport)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readerReport).To(Equal(report))
+		return report
+	}
+
+	It("should count lines in the same way", func() {
+		for _, eol := range []string{"\n", "\r\n", "\r"} {
+			robotstxt := strings.Join([]string{
+				"User-Agent: foo",
+				"Allow: /some/path",
+				"User-Agent: bar",
+				"",
+				"",
+				"Disallow: /",
+			}, eol)
+			report := parse(robotstxt)
+			Expect(report.validDirectives).To(Equal(4))
+			Expect(report.lastLineSeen).To(Equal(6))
+		}
+	})
+
+	It("should skip byte order marks in the same way", func() {
+		for _, bom := range []string{"\xEF\xBB\xBF", "\xEF\xBB", "\xEF"} {
+			report := parse(bom + "User-Agent: foo\nAllow: /AnyValue\n")
+			Expect(report.validDirectives).To(Equal(2))
+			Expect(report.unknownDirectives).To(Equal(0))
+		}
+		report := parse("\xEF\x11\xBFUser-Agent: foo\nAllow: /AnyValue\n")
+		Expect(report.validDirectives).To(Equal(1))
+		Expect(report.unknownDirectives).To(Equal(1))
+
+		parse("")
+		parse("\xEF")
+		parse("\xEF\xBB\xBF")
+	})
+
+	It("should cut long lines in the same way", func() {
+		const maxLineLen = 2083 * 8
+		longline := "/x/" + strings.Repeat("a", maxLineLen)
+		robotstxt := "user-agent: FooBot\ndisallow: " + longline + "/qux\n"
+		parse(robotstxt)
+
+		Expect(grobotstxt.AgentAllowedBytes([]byte(robotstxt), "FooBot", "http://foo.bar/fux")).To(BeTrue())
+		Expect(grobotstxt.AgentAllowedBytes([]byte(robotstxt), "FooBot", "http://foo.bar"+longline+"/fux")).To(BeFalse())
+	})
+
+	It("should cut oversized bodies in the same way", func() {
+		const header = "User-agent: *\nDisallow: /x\n"
+		for _, eol := range []string{"\n", "\r\n", "\r"} {
+			// Move the cut across the line endings and the last line.
+			for n := 0; n < 16; n++ {
+				padding := strings.Repeat("#", grobotstxt.DefaultMaxBytes-len(header)-n)
+				robotstxt := header + padding + eol + "Disallow: /abc" + eol + "Disallow: /y" + eol
+				parse(robotstxt)
+
+				expected := &callbackRecorder{}
+				grobotstxt.Parse(robotstxt, expected)
+				streamed := &callbackRecorder{}
+				err := grobotstxt.ParseReader(iotest.OneByteReader(strings.NewReader(robotstxt)), streamed)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(streamed.calls).To(Equal(expected.calls), "%q %d", eol, n)
+			}
+		}
+	})
+
+	It("should give the same verdicts", func() {
+		const robotstxt = "User-agent: *\n" +
+			"Disallow: /x/\n" +
+			"User-agent: FooBot\n" +
+			"Disallow: /\n" +
+			"Allow: /fish\n"
+		for _, uri := range []string{"http://foo.bar/", "http://foo.bar/x/", "http://foo.bar/fish"} {
+			for _, agent := range []string{"FooBot", "BarBot"} {
+				expected := grobotstxt.AgentAllowed(robotstxt, agent, uri)
+				Expect(grobotstxt.AgentAllowedBytes([]byte(robotstxt), agent, uri)).To(Equal(expected))
+				Expect(grobotstxt.AgentsAllowedBytes([]byte(robotstxt), []string{agent}, uri)).To(Equal(expected))
+
+				robots, err := grobotstxt.CompileBytes([]byte(robotstxt))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(robots.Allowed(agent, uri)).To(Equal(expected))
+
+				robots, err = grobotstxt.CompileReader(strings.NewReader(robotstxt))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(robots.Allowed(agent, uri)).To(Equal(expected))
+			}
+		}
+	})
+
+	It("should extract sitemaps", func() {
+		const robotstxt = "Sitemap: http://foo.bar/a.xml\nUser-agent: *\nSitemap: http://foo.bar/b.xml"
+		expected := []string{"http://foo.bar/a.xml", "http://foo.bar/b.xml"}
+		Expect(grobotstxt.SitemapsBytes([]byte(robotstxt))).To(Equal(expected))
+		sitemaps, err := grobotstxt.SitemapsReader(strings.NewReader(robotstxt))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sitemaps).To(Equal(expected))
+	})
+
+	It("should return read errors", func() {
+		readErr := errors.New("read failed")
+		r := &failingReader{r: strings.NewReader("User-agent: *\nDisallow: /\n"), err: readErr}
+		report := &robotsStatsReporter{}
+		Expect(grobotstxt.ParseReader(r, report)).To(Equal(readErr))
+		Expect(report.validDirectives).To(Equal(2))
+
+		r = &failingReader{r: strings.NewReader("Sitemap: http://foo.bar/a.xml\n"), err: readErr}
+		_, err := grobotstxt.SitemapsReader(r)
+		Expect(err).To(Equal(readErr))
+
+		r = &failingReader{r: strings.NewReader(""), err: readErr}
+		_, err = grobotstxt.CompileReader(r)
+		Expect(err).To(Equal(readErr))
+	})
+
+})
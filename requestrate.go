@@ -0,0 +1,199 @@
+package grobotstxt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestRate is the value of a "Request-rate:" line, from the extended
+// robots.txt standard. It asks crawlers to make at most Requests requests
+// per Period, e.g. "Request-rate: 1/10s".
+//
+// A request rate may be limited to a time of day, e.g.
+// "Request-rate: 1/10s 0100-0645", in which case Window is non-nil.
+type RequestRate struct {
+	Requests int
+	Period   time.Duration
+	Window   *VisitTime
+}
+
+// Delay returns the average delay between requests for the rate,
+// or zero if Requests is not positive.
+func (r RequestRate) Delay() time.Duration {
+	if r.Requests <= 0 {
+		return 0
+	}
+	return r.Period / time.Duration(r.Requests)
+}
+
+// String returns the rate in robots.txt syntax, e.g. "1/10s".
+func (r RequestRate) String() string {
+	s := strconv.Itoa(r.Requests) + "/" + formatPeriod(r.Period)
+	if r.Window != nil {
+		s += " " + r.Window.String()
+	}
+	return s
+}
+
+func formatPeriod(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return strconv.FormatInt(int64(d/time.Hour), 10) + "h"
+	case d%time.Minute == 0:
+		return strconv.FormatInt(int64(d/time.Minute), 10) + "m"
+	default:
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+}
+
+// VisitTime is the value of a "Visit-time:" line, from the extended
+// robots.txt standard. It asks crawlers to visit only between the times
+// of day Start and End, in UTC, e.g. "Visit-time: 0100-0645".
+//
+// Start and End are offsets from midnight, with minute resolution, and are
+// both inclusive. A window may wrap around midnight, e.g. "2300-0500".
+type VisitTime struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Contains returns true if the given time falls within the window.
+func (v VisitTime) Contains(t time.Time) bool {
+	t = t.UTC()
+	m := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if v.Start <= v.End {
+		return v.Start <= m && m <= v.End
+	}
+	return m >= v.Start || m <= v.End
+}
+
+// String returns the window in robots.txt syntax, e.g. "0100-0645".
+func (v VisitTime) String() string {
+	return formatClock(v.Start) + "-" + formatClock(v.End)
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d%02d", d/time.Hour, (d%time.Hour)/time.Minute)
+}
+
+// RequestRates parses the given robots.txt content, and returns the request
+// rates that apply to the given userAgents. See Checker.RequestRates.
+func RequestRates(robotsBody string, userAgents []string) []RequestRate {
+	c, err := NewChecker(robotsBody, userAgents...)
+	if err != nil {
+		return nil
+	}
+	return c.RequestRates()
+}
+
+// VisitTimes parses the given robots.txt content, and returns the visit
+// times that apply to the given userAgents. See Checker.VisitTimes.
+func VisitTimes(robotsBody string, userAgents []string) []VisitTime {
+	c, err := NewChecker(robotsBody, userAgents...)
+	if err != nil {
+		return nil
+	}
+	return c.VisitTimes()
+}
+
+// parseRequestRate parses the value of a "Request-rate:" line, which has the
+// form <requests>/<period>[unit] [window], where unit is one of s, m, h or d,
+// and defaults to seconds. It returns false if the value is not valid.
+func parseRequestRate(value string) (RequestRate, bool) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return RequestRate{}, false
+	}
+	slash := strings.IndexByte(fields[0], '/')
+	if slash == -1 {
+		return RequestRate{}, false
+	}
+	requests, err := strconv.Atoi(fields[0][:slash])
+	if err != nil || requests <= 0 {
+		return RequestRate{}, false
+	}
+	period, ok := parsePeriod(fields[0][slash+1:])
+	if !ok {
+		return RequestRate{}, false
+	}
+	r := RequestRate{
+		Requests: requests,
+		Period:   period,
+	}
+	if len(fields) > 1 {
+		window, ok := parseVisitTime(strings.Join(fields[1:], ""))
+		if !ok {
+			return RequestRate{}, false
+		}
+		r.Window = &window
+	}
+	return r, true
+}
+
+// maxPeriod is the longest period accepted in a "Request-rate:" line.
+const maxPeriod = 365 * 24 * time.Hour
+
+// parsePeriod parses the period of a "Request-rate:" line, such as "10s".
+// It returns false if the period is not valid, or is longer than maxPeriod.
+func parsePeriod(s string) (time.Duration, bool) {
+	unit := time.Second
+	if len(s) > 0 {
+		switch s[len(s)-1] {
+		case 's', 'S':
+			s = s[:len(s)-1]
+		case 'm', 'M':
+			unit = time.Minute
+			s = s[:len(s)-1]
+		case 'h', 'H':
+			unit = time.Hour
+			s = s[:len(s)-1]
+		case 'd', 'D':
+			unit = 24 * time.Hour
+			s = s[:len(s)-1]
+		}
+	}
+	n, err := strconv.Atoi(s)
+	// Bounding n by the unit also keeps n*unit from overflowing.
+	if err != nil || n <= 0 || n > int(maxPeriod/unit) {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// parseVisitTime parses the value of a "Visit-time:" line, which has the
+// form HHMM-HHMM. Colons within the times, and spaces, are tolerated.
+// It returns false if the value is not valid.
+func parseVisitTime(value string) (VisitTime, bool) {
+	value = strings.Join(strings.Fields(value), "")
+	dash := strings.IndexByte(value, '-')
+	if dash == -1 {
+		return VisitTime{}, false
+	}
+	start, ok := parseClock(value[:dash])
+	if !ok {
+		return VisitTime{}, false
+	}
+	end, ok := parseClock(value[dash+1:])
+	if !ok {
+		return VisitTime{}, false
+	}
+	return VisitTime{Start: start, End: end}, true
+}
+
+func parseClock(s string) (time.Duration, bool) {
+	s = strings.Replace(s, ":", "", 1)
+	if len(s) != 4 {
+		return 0, false
+	}
+	hh, err := strconv.Atoi(s[:2])
+	if err != nil || hh < 0 || hh > 23 {
+		return 0, false
+	}
+	mm, err := strconv.Atoi(s[2:])
+	if err != nil || mm < 0 || mm > 59 {
+		return 0, false
+	}
+	return time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute, true
+}
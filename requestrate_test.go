@@ -0,0 +1,91 @@
+package grobotstxt_test
+
+import (
+	"time"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequestRate and VisitTime", func() {
+
+	const robotstxt = "User-agent: *\n" +
+		"Request-rate: 1/5\n" +
+		"Visit-time: 2300-0500\n" +
+		"Disallow: /x/\n" +
+		"User-agent: FooBot\n" +
+		"Request-rate: 1/10s\n" +
+		"Request rate: 30/1m 0100-0645\n" +
+		"Request-rate: 0/1s\n" +
+		"Request-rate: fast\n" +
+		"Visit-time: 01:00 - 06:45\n" +
+		"Visit-time: 25:00-26:00\n" +
+		"Disallow: /\n"
+
+	It("should parse request rates", func() {
+		Expect(grobotstxt.RequestRates(robotstxt, []string{"BarBot"})).To(Equal([]grobotstxt.RequestRate{
+			{Requests: 1, Period: 5 * time.Second},
+		}))
+
+		rates := grobotstxt.RequestRates(robotstxt, []string{"FooBot"})
+		Expect(rates).To(Equal([]grobotstxt.RequestRate{
+			{Requests: 1, Period: 10 * time.Second},
+			{Requests: 30, Period: time.Minute, Window: &grobotstxt.VisitTime{
+				Start: time.Hour,
+				End:   6*time.Hour + 45*time.Minute,
+			}},
+		}))
+		Expect(rates[0].Delay()).To(Equal(10 * time.Second))
+		Expect(rates[1].Delay()).To(Equal(2 * time.Second))
+		Expect(rates[0].String()).To(Equal("1/10s"))
+		Expect(rates[1].String()).To(Equal("30/1m 0100-0645"))
+	})
+
+	It("should reject periods longer than a year", func() {
+		rates := grobotstxt.RequestRates("User-agent: *\n"+
+			"Request-rate: 1/365d\n"+
+			"Request-rate: 1/366d\n"+
+			"Request-rate: 1/9999999h\n"+
+			"Request-rate: 1/9223372036854775807s\n", []string{"FooBot"})
+		Expect(rates).To(Equal([]grobotstxt.RequestRate{
+			{Requests: 1, Period: 365 * 24 * time.Hour},
+		}))
+	})
+
+	It("should parse visit times", func() {
+		Expect(grobotstxt.VisitTimes(robotstxt, []string{"FooBot"})).To(Equal([]grobotstxt.VisitTime{
+			{Start: time.Hour, End: 6*time.Hour + 45*time.Minute},
+		}))
+		Expect(grobotstxt.VisitTimes("", []string{"FooBot"})).To(BeEmpty())
+	})
+
+	It("should check visit times alongside the verdict", func() {
+		at := func(hour, min int) time.Time {
+			return time.Date(2020, 4, 21, hour, min, 0, 0, time.UTC)
+		}
+
+		checker, err := grobotstxt.NewChecker(robotstxt, "FooBot")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checker.Allowed("http://foo.bar/")).To(BeFalse())
+		Expect(checker.VisitAllowed(at(0, 59))).To(BeFalse())
+		Expect(checker.VisitAllowed(at(1, 0))).To(BeTrue())
+		Expect(checker.VisitAllowed(at(6, 45))).To(BeTrue())
+		Expect(checker.VisitAllowed(at(6, 46))).To(BeFalse())
+
+		// Windows may wrap around midnight, and are in UTC.
+		checker, err = grobotstxt.NewChecker(robotstxt, "BarBot")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checker.Allowed("http://foo.bar/")).To(BeTrue())
+		Expect(checker.VisitAllowed(at(23, 30))).To(BeTrue())
+		Expect(checker.VisitAllowed(at(4, 0))).To(BeTrue())
+		Expect(checker.VisitAllowed(at(12, 0))).To(BeFalse())
+		Expect(checker.VisitAllowed(at(12, 0).In(time.FixedZone("UTC+13", 13*60*60)))).To(BeFalse())
+
+		// No visit times: any time is fine.
+		checker, err = grobotstxt.NewChecker("", "FooBot")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checker.VisitAllowed(at(12, 0))).To(BeTrue())
+	})
+
+})
@@ -0,0 +1,201 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// This file checks strict mode against the examples of RFC 9309.
+// https://www.rfc-editor.org/rfc/rfc9309.html
+
+var _ = Describe("RFC 9309", func() {
+
+	strictAllowed := func(robotstxt, userAgent, uri string) bool {
+		m := grobotstxt.NewRobotsMatcher()
+		m.Strict = true
+		return m.AgentAllowed(robotstxt, userAgent, uri)
+	}
+
+	googleAllowed := func(robotstxt, userAgent, uri string) bool {
+		return grobotstxt.AgentAllowed(robotstxt, userAgent, uri)
+	}
+
+	// Section 5.1.
+	It("should match the simple example", func() {
+		const robotstxt = "User-Agent: *\n" +
+			"Disallow: *.gif$\n" +
+			"Disallow: /example/\n" +
+			"Allow: /publications/\n" +
+			"\n" +
+			"User-Agent: foobot\n" +
+			"Disallow:/\n" +
+			"Allow:/example/page.html\n" +
+			"Allow:/example/allowed.gif\n" +
+			"\n" +
+			"User-Agent: barbot\n" +
+			"User-Agent: bazbot\n" +
+			"Disallow: /example/page.html\n" +
+			"\n" +
+			"User-Agent: quxbot\n" +
+			"\n"
+
+		type verdicts map[string]bool
+		expected := map[string]verdicts{
+			"foobot": {
+				"/example/page.html":   true,
+				"/example/allowed.gif": true,
+				"/example/other.html":  false,
+				"/publications/":       false,
+				"/":                    false,
+			},
+			"barbot": {
+				"/example/page.html":   false,
+				"/example/allowed.gif": true,
+				"/":                    true,
+			},
+			"bazbot": {
+				"/example/page.html": false,
+				"/publications/":     true,
+			},
+			"quxbot": {
+				"/example/page.html": true,
+				"/image.gif":         true,
+			},
+			"otherbot": {
+				"/example/page.html":  false,
+				"/image.gif":          false,
+				"/publications/":      true,
+				"/publications/a.gif": true,
+				"/":                   true,
+			},
+		}
+		for agent, paths := range expected {
+			for path, allowed := range paths {
+				uri := "http://example.com" + path
+				Expect(strictAllowed(robotstxt, agent, uri)).To(Equal(allowed), agent+" "+path)
+				Expect(googleAllowed(robotstxt, agent, uri)).To(Equal(allowed), agent+" "+path)
+			}
+		}
+	})
+
+	// Section 5.2.
+	It("should use the longest match", func() {
+		const robotstxt = "User-Agent: foobot\n" +
+			"Allow: /example/page/\n" +
+			"Disallow: /example/page/disallowed.gif\n"
+
+		Expect(strictAllowed(robotstxt, "foobot", "http://example.com/example/page/")).To(BeTrue())
+		Expect(strictAllowed(robotstxt, "foobot", "http://example.com/example/page/disallowed.gif")).To(BeFalse())
+	})
+
+	// Section 2.2.2: octets outside US-ASCII are percent-encoded.
+	It("should match percent-encoded paths", func() {
+		for _, pattern := range []string{"/foo/bar/ツ", "/foo/bar/%E3%83%84", "/foo/bar/%e3%83%84"} {
+			robotstxt := "User-agent: *\nDisallow: " + pattern + "\n"
+			Expect(strictAllowed(robotstxt, "foobot", "http://example.com/foo/bar/ツ")).To(BeFalse(), pattern)
+			Expect(strictAllowed(robotstxt, "foobot", "http://example.com/foo/bar/%E3%83%84")).To(BeFalse(), pattern)
+		}
+		const robotstxt = "User-agent: *\nDisallow: /foo/bar?baz=quz\n"
+		Expect(strictAllowed(robotstxt, "foobot", "http://example.com/foo/bar?baz=quz")).To(BeFalse())
+	})
+
+	// Section 2.2.3.
+	It("should support the special characters", func() {
+		type verdicts map[string]bool
+		for pattern, paths := range map[string]verdicts{
+			"*.gif$":  {"/a.gif": false, "/a/b.gif": false, "/a.gif?x": true},
+			"/*.php$": {"/filename.php": false, "/filename.php?x": true, "/filename.php5": true},
+			"/$":      {"/": false, "/a": true},
+			"/fish*":  {"/fish": false, "/fishheads": false, "/catfish": true},
+		} {
+			robotstxt := "User-agent: *\nDisallow: " + pattern + "\n"
+			for path, allowed := range paths {
+				Expect(strictAllowed(robotstxt, "foobot", "http://example.com"+path)).To(Equal(allowed), pattern+" "+path)
+			}
+		}
+	})
+
+	// Section 2.2.1: the product token is matched case-insensitively.
+	It("should match product tokens case-insensitively", func() {
+		const robotstxt = "user-agent: FooBot\ndisallow: /\n"
+		Expect(strictAllowed(robotstxt, "foobot", "http://example.com/")).To(BeFalse())
+		Expect(strictAllowed(robotstxt, "FOOBOT", "http://example.com/")).To(BeFalse())
+	})
+
+	It("should not accept Google's extensions", func() {
+		const url = "http://example.com/x/y"
+		for robotstxt, googleVerdict := range map[string]bool{
+			// Typos and prefixes of keys.
+			"useragent: FooBot\ndisallow: /\n":    false,
+			"user-agent: FooBot\ndisalow: /\n":    false,
+			"user-agent: FooBot\ndisallowed: /\n": false,
+			// Missing colons.
+			"user-agent FooBot\ndisallow: /\n": false,
+			"user-agent: FooBot\ndisallow /\n": false,
+			// Global agent followed by other text.
+			"user-agent: * FooBot\ndisallow: /\n": false,
+			// User-agent values that are not product tokens.
+			"user-agent: FooBot/1.2\ndisallow: /\n": false,
+			// Patterns that do not start with '/'.
+			"user-agent: FooBot\ndisallow: x\n":        true,
+			"user-agent: FooBot\ndisallow: http://e\n": true,
+		} {
+			Expect(googleAllowed(robotstxt, "FooBot", url)).To(Equal(googleVerdict), robotstxt)
+			Expect(strictAllowed(robotstxt, "FooBot", url)).To(BeTrue(), robotstxt)
+		}
+
+		// 'index.html' is not normalised to '/'.
+		const robotstxt = "user-agent: FooBot\n" +
+			"disallow: /\n" +
+			"allow: /allowed-slash/index.html\n"
+		Expect(googleAllowed(robotstxt, "FooBot", "http://example.com/allowed-slash/")).To(BeTrue())
+		Expect(strictAllowed(robotstxt, "FooBot", "http://example.com/allowed-slash/")).To(BeFalse())
+	})
+
+	It("should make the parser strict", func() {
+		report := &robotsStatsReporter{}
+		p := grobotstxt.NewParser("useragent: FooBot\n"+
+			"user-agent FooBot\n"+
+			"user-agent: FooBot\n"+
+			"disallow: x\n"+
+			"crawl-delay: 1\n"+
+			"crawldelay: 1\n", report)
+		p.Strict = true
+		p.Parse()
+		// The invalid Disallow is still passed on, as it ends a group.
+		Expect(report.validDirectives).To(Equal(2))
+		Expect(report.unknownDirectives).To(Equal(3))
+	})
+
+	// Section 2.1: a group's rules are followed by the next group's
+	// User-agent lines, even invalid ones.
+	It("should end groups at invalid lines", func() {
+		const url = "http://example.com/x"
+
+		robotstxt := "User-agent: BarBot\n" +
+			"Disallow: /a\n" +
+			"\n" +
+			"User-agent: FooBot/1.2\n" +
+			"Disallow: /\n"
+		Expect(googleAllowed(robotstxt, "BarBot", url)).To(BeTrue())
+		Expect(strictAllowed(robotstxt, "BarBot", url)).To(BeTrue())
+		Expect(strictAllowed(robotstxt, "BarBot", "http://example.com/a")).To(BeFalse())
+
+		robotstxt = "User-agent: *\n" +
+			"Disallow: /a\n" +
+			"\n" +
+			"User-agent: * FooBot\n" +
+			"Disallow: /\n"
+		Expect(strictAllowed(robotstxt, "Other", url)).To(BeTrue())
+		Expect(strictAllowed(robotstxt, "Other", "http://example.com/a")).To(BeFalse())
+
+		robotstxt = "User-agent: BarBot\n" +
+			"Disallow: x\n" +
+			"User-agent: FooBot\n" +
+			"Disallow: /\n"
+		Expect(googleAllowed(robotstxt, "BarBot", url)).To(BeTrue())
+		Expect(strictAllowed(robotstxt, "BarBot", url)).To(BeTrue())
+	})
+
+})
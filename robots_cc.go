@@ -29,13 +29,23 @@
 package grobotstxt
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
+	"io"
 	"net/url"
+	"strconv"
 	"strings"
 	"unicode"
 )
 
 // AllowFrequentTypos enables the parsing of common typos in robots.txt, such as DISALOW.
+//
+// Deprecated: Use Options.Typos instead. AllowFrequentTypos sets Typos
+// whenever a Parser or RobotsMatcher is created without Options, which
+// includes calls to package-level funcs such as Parse and AgentAllowed.
+// Changing it while other goroutines may be parsing is therefore a data
+// race. Options do not have this problem.
 var AllowFrequentTypos = true
 
 // A MatchStrategy defines a strategy for matching individual lines in a
@@ -44,12 +54,11 @@ var AllowFrequentTypos = true
 // Each Match* method should return a match priority, which is
 // interpreted as:
 //
-//  match priority < 0:  No match.
-//
-//  match priority == 0: Match, but treat it as if matched an empty pattern.
+//	match priority < 0:  No match.
 //
-//  match priority > 0:  Match.
+//	match priority == 0: Match, but treat it as if matched an empty pattern.
 //
+//	match priority > 0:  Match.
 type MatchStrategy interface {
 	MatchAllow(path, pattern string) int
 	MatchDisallow(path, pattern string) int
@@ -66,7 +75,13 @@ func Matches(path, pattern string) bool {
 	// Line :69
 	// This method originally belonged to abstract base class RobotsMatchStrategy.
 	pathlen := len(path)
-	pos := make([]int, pathlen+1)
+	var buf [128]int // Avoids allocating for typical paths.
+	var pos []int
+	if pathlen < len(buf) {
+		pos = buf[:pathlen+1]
+	} else {
+		pos = make([]int, pathlen+1)
+	}
 	var numpos int
 
 	// The pos[] array holds a sorted list of indexes of 'path', with length
@@ -181,8 +196,10 @@ func findByte(s string, b byte, i int) int {
 // and any existing percent-encoded values have their hex values normalised to uppercase.
 //
 // For example:
-//     /SanJoséSellers ==> /Sanjos%C3%A9Sellers
-//     %aa ==> %AA
+//
+//	/SanJoséSellers ==> /Sanjos%C3%A9Sellers
+//	%aa ==> %AA
+//
 // If the given path pattern is already adequately escaped,
 // the original string is returned unchanged.
 func escapePattern(path string) string {
@@ -255,8 +272,14 @@ func isLower(c byte) bool {
 	return 'a' <= c && c <= 'z'
 }
 
+// toUpper returns the upper case of an ASCII letter, and any other byte
+// unchanged, as absl::ascii_toupper does. Clearing bit 0x20 instead
+// would also change digits, such as '2' in "%2f".
 func toUpper(c byte) byte {
-	return c & (0xFF - 0x20)
+	if isLower(c) {
+		return c - ('a' - 'A')
+	}
+	return c
 }
 
 //
@@ -272,10 +295,38 @@ const (
 	sitemapKey   // sitemapKey for "Sitemap:" keys.
 
 	// Fields within a user-agent group/section.
-	allowKey    // allowKey for "Allow:" keys.
-	disallowKey // disallowKey for "Disallow:" keys.
+	allowKey       // allowKey for "Allow:" keys.
+	disallowKey    // disallowKey for "Disallow:" keys.
+	crawlDelayKey  // crawlDelayKey for "Crawl-delay:" keys.
+	requestRateKey // requestRateKey for "Request-rate:" keys.
+	visitTimeKey   // visitTimeKey for "Visit-time:" keys.
+
+	// AI usage preference fields, within a user-agent group/section.
+	contentSignalKey // contentSignalKey for "Content-Signal:" keys.
+	contentUsageKey  // contentUsageKey for "Content-Usage:" keys.
+
+	// Yandex-specific fields, which apply to the whole file.
+	hostKey       // hostKey for "Host:" keys.
+	cleanParamKey // cleanParamKey for "Clean-param:" keys.
+
+	customKey // customKey for keys declared in Options.Directives.
 )
 
+// keyNames holds the canonical spelling of each known key.
+var keyNames = map[keyType]string{
+	userAgentKey:     "User-agent",
+	sitemapKey:       "Sitemap",
+	allowKey:         "Allow",
+	disallowKey:      "Disallow",
+	crawlDelayKey:    "Crawl-delay",
+	requestRateKey:   "Request-rate",
+	visitTimeKey:     "Visit-time",
+	contentSignalKey: "Content-Signal",
+	contentUsageKey:  "Content-Usage",
+	hostKey:          "Host",
+	cleanParamKey:    "Clean-param",
+}
+
 //
 
 // A robots.txt has lines of key/value pairs. A ParsedRobotsKey represents
@@ -287,21 +338,53 @@ const (
 type parsedKey struct {
 	typ keyType
 	key string
+
+	directive *Directive // Declaration of a customKey.
 }
 
 // parseKey parses given key text, returning a suitably initialised parsedKey.
+// Keys are matched as by a Parser created without Options.
 func parseKey(key string) parsedKey {
+	opts := optionsOf(nil)
+	return parseKeyAs(key, opts.keys())
+}
+
+// parseKeyAs is like parseKey, but keys are matched as m says.
+func parseKeyAs(key string, m keyMatch) parsedKey {
 
 	// Line :659
 	k := parsedKey{}
-	if keyIsUserAgent(key) {
+	if keyIsUserAgent(key, m) {
 		k.typ = userAgentKey
-	} else if keyIsAllow(key) {
+	} else if keyIsAllow(key, m) {
 		k.typ = allowKey
-	} else if keyIsDisallow(key) {
+	} else if keyIsDisallow(key, m) {
 		k.typ = disallowKey
-	} else if keyIsSitemap(key) {
+	} else if keyIsSitemap(key, m) {
 		k.typ = sitemapKey
+	} else if keyIsCrawlDelay(key, m) {
+		// Extension keys keep their text, for handlers that
+		// only receive them as unknown actions.
+		k.typ = crawlDelayKey
+		k.key = key
+	} else if keyIsRequestRate(key, m) {
+		k.typ = requestRateKey
+		k.key = key
+	} else if keyIsVisitTime(key, m) {
+		k.typ = visitTimeKey
+		k.key = key
+	} else if keyIsContentSignal(key, m) {
+		k.typ = contentSignalKey
+		k.key = key
+	} else if keyIsContentUsage(key, m) {
+		k.typ = contentUsageKey
+		k.key = key
+	} else if keyIsHost(key) { // Whatever m, as Host has no typos or prefixes.
+		k.typ = hostKey
+		k.key = key
+	} else if keyIsCleanParam(key, m) {
+		k.typ = cleanParamKey
+		k.key = key
 	} else {
 		k.typ = unknownKey
 		k.key = key
@@ -309,45 +392,152 @@ func parseKey(key string) parsedKey {
 	return k
 }
 
+// parseStrictKey is like parseKey, but only accepts keys that are spelled
+// exactly as standardised, ignoring case.
+func parseStrictKey(key string) parsedKey {
+	for typ, name := range keyNames {
+		if strings.EqualFold(key, name) {
+			k := parsedKey{typ: typ}
+			if typ != userAgentKey && typ != allowKey && typ != disallowKey && typ != sitemapKey {
+				k.key = key
+			}
+			return k
+		}
+	}
+	return parsedKey{typ: unknownKey, key: key}
+}
+
+// isStrictValue returns true if the value is valid for the key, as per
+// the grammar of RFC 9309.
+func isStrictValue(key parsedKey, value string) bool {
+	switch key.Type() {
+	case userAgentKey:
+		return value == "*" || isProductToken(value)
+	case allowKey, disallowKey:
+		// Patterns starting with '*' are not in the RFC's grammar,
+		// but are used in its examples.
+		return value == "" || value[0] == '/' || value[0] == '*'
+	}
+	return true
+}
+
+// isProductToken returns true if s is a product token, as per RFC 9309:
+// one or more of the characters [a-zA-Z_-].
+func isProductToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !(asciiIsAlpha(c) || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
 // Type returns the type of key.
 func (k parsedKey) Type() keyType {
 	return k.typ
 }
 
-// UnknownKey returns the text of the key for Unknown key types.
+// UnknownKey returns the text of the key for Unknown and extension key types.
 // For all other key types it returns an empty string.
 func (k parsedKey) UnknownKey() string {
 	// Line :675
 	return k.key
 }
 
-func keyIsUserAgent(key string) bool {
+// keyMatch says how keys are matched against their spellings.
+type keyMatch struct {
+	typos    bool // Accept common misspellings. See Options.Typos.
+	prefixes bool // Accept keys that start with a spelling. See Options.KeyPrefixes.
+}
+
+// googleKeys is how Google matches keys, with all of its misspellings.
+var googleKeys = keyMatch{typos: true, prefixes: true}
+
+// is returns true if key is the given spelling, ignoring case,
+// or if m.prefixes is true, starts with it.
+func (m keyMatch) is(key, spelling string) bool {
+	if m.prefixes {
+		return startsWithIgnoreCase(key, spelling)
+	}
+	return strings.EqualFold(key, spelling)
+}
+
+func keyIsUserAgent(key string, m keyMatch) bool {
 	// Line :680
-	return startsWithIgnoreCase(key, "user-agent") ||
-		(AllowFrequentTypos && (startsWithIgnoreCase(key, "useragent") ||
-			startsWithIgnoreCase(key, "user agent")))
+	return m.is(key, "user-agent") ||
+		(m.typos && (m.is(key, "useragent") ||
+			m.is(key, "user agent")))
 }
 
-func keyIsAllow(key string) bool {
+func keyIsAllow(key string, m keyMatch) bool {
 	// Line :687
-	return startsWithIgnoreCase(key, "allow")
+	return m.is(key, "allow")
 }
 
-func keyIsDisallow(key string) bool {
+func keyIsDisallow(key string, m keyMatch) bool {
 	// Line :691
-	return startsWithIgnoreCase(key, "disallow") ||
-		(AllowFrequentTypos && (startsWithIgnoreCase(key, "dissallow") ||
-			startsWithIgnoreCase(key, "dissalow") ||
-			startsWithIgnoreCase(key, "disalow") ||
-			startsWithIgnoreCase(key, "diaslow") ||
-			startsWithIgnoreCase(key, "diasllow") ||
-			startsWithIgnoreCase(key, "disallaw")))
+	return m.is(key, "disallow") ||
+		(m.typos && (m.is(key, "dissallow") ||
+			m.is(key, "dissalow") ||
+			m.is(key, "disalow") ||
+			m.is(key, "diaslow") ||
+			m.is(key, "diasllow") ||
+			m.is(key, "disallaw")))
 }
 
-func keyIsSitemap(key string) bool {
+func keyIsSitemap(key string, m keyMatch) bool {
 	// Line :701
-	return startsWithIgnoreCase(key, "sitemap") ||
-		startsWithIgnoreCase(key, "site-map")
+	return m.is(key, "sitemap") ||
+		m.is(key, "site-map")
+}
+
+func keyIsCrawlDelay(key string, m keyMatch) bool {
+	return m.is(key, "crawl-delay") ||
+		(m.typos && (m.is(key, "crawldelay") ||
+			m.is(key, "crawl delay") ||
+			m.is(key, "crawl_delay") ||
+			m.is(key, "craw-delay") ||
+			m.is(key, "crawl-dealy")))
+}
+
+func keyIsRequestRate(key string, m keyMatch) bool {
+	return m.is(key, "request-rate") ||
+		(m.typos && (m.is(key, "requestrate") ||
+			m.is(key, "request rate") ||
+			m.is(key, "request_rate")))
+}
+
+func keyIsVisitTime(key string, m keyMatch) bool {
+	return m.is(key, "visit-time") ||
+		(m.typos && (m.is(key, "visittime") ||
+			m.is(key, "visit time") ||
+			m.is(key, "visit_time")))
+}
+
+func keyIsContentSignal(key string, m keyMatch) bool {
+	return m.is(key, "content-signal") ||
+		(m.typos && m.is(key, "content signal"))
+}
+
+func keyIsContentUsage(key string, m keyMatch) bool {
+	return m.is(key, "content-usage") ||
+		(m.typos && m.is(key, "content usage"))
+}
+
+// keyIsHost only matches "Host" itself, unlike other keys, so that keys
+// such as "Hostname" are not taken for it. It is not affected by Options.
+func keyIsHost(key string) bool {
+	return strings.EqualFold(key, "host")
+}
+
+func keyIsCleanParam(key string, m keyMatch) bool {
+	return m.is(key, "clean-param") ||
+		(m.typos && (m.is(key, "cleanparam") ||
+			m.is(key, "clean param") ||
+			m.is(key, "clean_param")))
 }
 
 func startsWithIgnoreCase(x, y string) bool {
@@ -367,33 +557,142 @@ func emitKeyValueToHandler(line int, key parsedKey, value string, handler ParseH
 		handler.HandleDisallow(line, value)
 	case sitemapKey:
 		handler.HandleSitemap(line, value)
+	case crawlDelayKey, requestRateKey, visitTimeKey,
+		contentSignalKey, contentUsageKey, hostKey, cleanParamKey:
+		if !emitExtensionToHandler(line, key, value, handler) {
+			handler.HandleUnknownAction(line, key.UnknownKey(), value)
+		}
+	case customKey:
+		if h, ok := handler.(DirectiveHandler); ok {
+			h.HandleDirective(line, *key.directive, value)
+		} else {
+			handler.HandleUnknownAction(line, key.UnknownKey(), value)
+		}
 	case unknownKey:
 		handler.HandleUnknownAction(line, key.UnknownKey(), value)
 	}
 }
 
+// emitExtensionToHandler emits an extension directive to the handler, if it
+// implements the matching handler interface, and returns false otherwise.
+func emitExtensionToHandler(line int, key parsedKey, value string, handler ParseHandler) bool {
+	switch key.Type() {
+	case crawlDelayKey:
+		if h, ok := handler.(CrawlDelayHandler); ok {
+			h.HandleCrawlDelay(line, value)
+			return true
+		}
+	case requestRateKey:
+		if h, ok := handler.(RequestRateHandler); ok {
+			h.HandleRequestRate(line, value)
+			return true
+		}
+	case visitTimeKey:
+		if h, ok := handler.(VisitTimeHandler); ok {
+			h.HandleVisitTime(line, value)
+			return true
+		}
+	case contentSignalKey:
+		if h, ok := handler.(ContentSignalHandler); ok {
+			h.HandleContentSignal(line, value)
+			return true
+		}
+	case contentUsageKey:
+		if h, ok := handler.(ContentUsageHandler); ok {
+			h.HandleContentUsage(line, value)
+			return true
+		}
+	case hostKey:
+		if h, ok := handler.(HostHandler); ok {
+			h.HandleHost(line, value)
+			return true
+		}
+	case cleanParamKey:
+		if h, ok := handler.(CleanParamHandler); ok {
+			h.HandleCleanParam(line, value)
+			return true
+		}
+	}
+	return false
+}
+
 //
 
+// Parser parses a robots.txt body, and emits its directives to a ParseHandler.
 type Parser struct {
+	// Strict selects strict RFC 9309 parsing, without Google's extensions:
+	// keys must be spelled exactly, lines must have a ':' separator,
+	// User-agent values must be "*" or a product token made of the
+	// characters [a-zA-Z_-], and Allow and Disallow patterns must be empty,
+	// or start with '/' or '*'. Other lines are ignored.
+	//
+	// User-agent, Allow and Disallow lines with invalid values are still
+	// passed to the handler, because they end the current group, as any
+	// other line of a group does. A strict RobotsMatcher matches no user
+	// agent to such a User-agent line, and ignores such rules.
+	//
+	// Compile, and Checker, do not support Strict.
+	//
+	// See https://www.rfc-editor.org/rfc/rfc9309.html
+	Strict bool
+
+	// MaxBytes is the number of bytes of the robots.txt body that are
+	// parsed. Content after that is ignored, as Google and RFC 9309 do.
+	// Zero means DefaultMaxBytes, and a negative value means no limit.
+	MaxBytes int
+
 	// Line :278
-	robotsBody string // TODO Should be []byte
+	robotsBody memBody       // Body held in memory, sliced into lines.
+	reader     io.ByteReader // Streamed body, read instead of robotsBody if non-nil.
 	handler    ParseHandler
+	err        error // First error seen by Parse.
+	truncated  int   // Line at which the body was cut short by MaxBytes, or 0.
+	seenAgent  bool  // True if any User-agent line has been emitted.
+
+	opts     Options
+	diagnose func(Diagnostic) // Receives diagnostics, if non-nil. See Lint.
+
+	// Receives each key and value before the value is escaped, if non-nil.
+	onValue func(lineNum int, key parsedKey, value string)
 }
 
-func NewParser(robotsBody string, handler ParseHandler) *Parser {
+// NewParser creates a Parser for the given robots.txt content. Options,
+// if given, control which of Google's extensions are accepted. Otherwise
+// DefaultOptions are used. Only the first Options is used.
+func NewParser(robotsBody string, handler ParseHandler, opts ...Options) *Parser {
 	// Line :282
+	return newParser(stringBody(robotsBody), handler, optionsOf(opts))
+}
+
+func newParser(robotsBody memBody, handler ParseHandler, opts Options) *Parser {
 	p := Parser{
 		robotsBody: robotsBody,
 		handler:    handler,
+		opts:       opts,
+	}
+	return &p
+}
+
+// newReaderParser creates a Parser that streams the robots.txt content
+// from the given reader, a byte at a time. It can only be parsed once.
+func newReaderParser(r io.ByteReader, handler ParseHandler, opts Options) *Parser {
+	p := Parser{
+		reader:  r,
+		handler: handler,
+		opts:    opts,
 	}
 	return &p
 }
 
-func (p *Parser) needEscapeValueForKey(key parsedKey) bool {
+// needEscapeValueForKey returns true if the values of the given key are
+// patterns, which are escaped before they are emitted.
+func needEscapeValueForKey(key parsedKey) bool {
 	// Line :300
 	switch key.Type() {
-	case userAgentKey, sitemapKey:
+	case userAgentKey, sitemapKey, hostKey:
 		return false
+	case customKey:
+		return key.directive.Escape
 	default:
 		return true
 	}
@@ -403,7 +702,7 @@ func (p *Parser) needEscapeValueForKey(key parsedKey) bool {
 //
 // On success, the parsed key and value, and true, are returned. If parsing is
 // unsuccessful, parseKeyAndValue returns two empty strings and false.
-func (p *Parser) parseKeyAndValue(line string) (string, string, bool) {
+func (p *Parser) parseKeyAndValue(lineNum int, line string) (string, string, bool) {
 	// Line :317
 	// Remove comments from the current robots.txt line.
 	comment := strings.IndexByte(line, '#')
@@ -415,7 +714,7 @@ func (p *Parser) parseKeyAndValue(line string) (string, string, bool) {
 	// Rules must match the following pattern:
 	//   <key>[ \t]*:[ \t]*<value>
 	sep := strings.IndexByte(line, ':')
-	if sep == -1 {
+	if sep == -1 && p.options().MissingColons {
 		// Google-specific optimization: some people forget the colon, so we need to
 		// accept whitespace in its stead.
 		white := " \t"
@@ -423,19 +722,26 @@ func (p *Parser) parseKeyAndValue(line string) (string, string, bool) {
 		if sep != -1 {
 			val := strings.TrimSpace(line[sep:])
 			if len(val) == 0 { // since we dropped trailing whitespace above.
-				panic("Syntax error") // TODO Cleanup panics.
+				p.fail(&ParseError{Line: lineNum, Kind: InvalidSyntax})
+				p.report(lineNum, Error, InvalidLine, "invalid syntax")
+				return "", "", false
 			}
 			if strings.IndexAny(val, white) != -1 {
 				// We only accept whitespace as a separator if there are exactly two
 				// sequences of non-whitespace characters.  If we get here, there were
 				// more than 2 such sequences since we stripped trailing whitespace
 				// above.
+				p.report(lineNum, Error, InvalidLine, "line has no ':' separator")
 				return "", "", false
 			}
+			p.report(lineNum, Warning, MissingColon, "missing ':' after "+strconv.Quote(line[:sep]))
 		}
 	}
 
 	if sep == -1 {
+		if len(line) != 0 {
+			p.report(lineNum, Error, InvalidLine, "line has no ':' separator")
+		}
 		return "", "", false // Couldn't find a separator.
 	}
 
@@ -443,6 +749,7 @@ func (p *Parser) parseKeyAndValue(line string) (string, string, bool) {
 	key = strings.TrimSpace(key) // Get rid of any trailing whitespace.
 
 	if len(key) == 0 {
+		p.report(lineNum, Error, InvalidLine, "line has no key before ':'")
 		return "", "", false
 	}
 
@@ -453,84 +760,297 @@ func (p *Parser) parseKeyAndValue(line string) (string, string, bool) {
 
 func (p *Parser) parseAndEmitLine(currentLine int, line string) {
 	// Line :362
-	stringKey, value, ok := p.parseKeyAndValue(line)
+	stringKey, value, ok := p.parseKeyAndValue(currentLine, line)
 	if !ok {
 		return
 	}
+	p.emit(currentLine, stringKey, value)
+}
 
-	key := parseKey(stringKey)
-	if p.needEscapeValueForKey(key) {
+// emit parses the given key, escapes the value if needed, and emits them to the handler.
+func (p *Parser) emit(currentLine int, stringKey, value string) {
+	opts := p.options()
+	key := parseKeyAs(stringKey, opts.keys())
+	if p.Strict {
+		key = parseStrictKey(stringKey)
+	}
+	if key.Type() == unknownKey {
+		if d := opts.Directives.lookup(stringKey, opts.Typos); d != nil {
+			key = parsedKey{typ: customKey, key: stringKey, directive: d}
+		}
+	}
+	switch {
+	case key.Type() == userAgentKey:
+		p.seenAgent = true
+	case key.Type() == customKey && key.directive.Scope == GroupScope && !p.seenAgent:
+		p.report(currentLine, Error, RuleBeforeUserAgent,
+			strconv.Quote(key.directive.Name)+" comes before any user-agent, and is ignored")
+		return
+	}
+	if p.Strict && !isStrictValue(key, value) {
+		p.report(currentLine, Error, InvalidLine, "invalid "+keyNames[key.Type()]+" value "+strconv.Quote(value))
+	}
+	if p.diagnose != nil {
+		p.diagnoseKey(currentLine, stringKey, key)
+	}
+	if p.onValue != nil {
+		p.onValue(currentLine, key, value)
+	}
+	if needEscapeValueForKey(key) {
 		value = escapePattern(value)
 	}
 	emitKeyValueToHandler(currentLine, key, value, p.handler)
 }
 
-// Parse body of this Parser's robots.txt and emit parse callbacks. This will accept
-// typical typos found in robots.txt, such as 'disalow'.
+// Parse body of this Parser's robots.txt and emit parse callbacks. Unless turned
+// off by its Options, this will accept typical typos found in robots.txt, such as 'disalow'.
 //
 // Note, this function will accept all kind of input but will skip
 // everything that does not look like a robots directive.
+//
+// Parse never panics. Any error is available from Err afterwards.
 func (p *Parser) Parse() {
-	// TODO see line :381
-	// UTF-8 byte order marks.
-	utfBOM := []byte{0xEF, 0xBB, 0xBF}
+	p.err = nil
+	p.truncated = 0
+	p.seenAgent = false
+	if err := p.parse(); err != nil {
+		p.err = err
+	}
+}
+
+// Err returns the first error encountered by the last call to Parse,
+// or nil if there was none. Errors are either a *ParseError, or an
+// error from reading the robots.txt body, which stops parsing.
+func (p *Parser) Err() error {
+	return p.err
+}
 
-	// Certain browsers limit the URL length to 2083 bytes. In a robots.txt, it's
-	// fairly safe to assume any valid line isn't going to be more than many times
-	// that max url length of 2KB. We want some padding for
-	// UTF-8 encoding/nulls/etc. but a much smaller bound would be okay as well.
-	// If so, we can ignore the chars on a line past that.
-	const maxLineLen = 2083 * 8
+// Truncated reports whether the last call to Parse stopped at MaxBytes,
+// and if so, the number of the line that was cut. That line is parsed
+// up to the cut, and any lines after it are ignored.
+func (p *Parser) Truncated() (lineNum int, ok bool) {
+	return p.truncated, p.truncated != 0
+}
 
-	var b byte
+func (p *Parser) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
 
+// Certain browsers limit the URL length to 2083 bytes. In a robots.txt, it's
+// fairly safe to assume any valid line isn't going to be more than many times
+// that max url length of 2KB. We want some padding for
+// UTF-8 encoding/nulls/etc. but a much smaller bound would be okay as well.
+// If so, we can ignore the chars on a line past that.
+const maxLineLen = 2083 * 8
+
+// DefaultMaxBytes is the default value of Parser.MaxBytes. Google ignores
+// content after the first 500 KiB of a robots.txt file, and RFC 9309
+// requires crawlers to parse at least that much.
+const DefaultMaxBytes = 500 * 1024
+
+// utfBOM is the UTF-8 byte order mark.
+const utfBOM = "\xEF\xBB\xBF"
+
+// parse is Parse, but returns any error from reading the robots.txt body.
+// After an error, the handler will have seen the lines read before it,
+// and HandleRobotsEnd is not called.
+func (p *Parser) parse() error {
+	// TODO see line :381
 	p.handler.HandleRobotsStart()
 
-	length := len(p.robotsBody)
-	cur := 0
-	// Skip BOM if present - including partial BOMs.
-	for i := 0; i < len(utfBOM); i++ {
-		if cur == length {
-			break
+	opts := p.options()
+	var lineNum int
+	var last string
+	var truncated bool
+	var err error
+	if p.reader != nil {
+		lineNum, last, truncated, err = p.readLines(opts)
+	} else {
+		lineNum, last, truncated, err = p.sliceLines(opts)
+	}
+	if err == errBodyTooLarge {
+		// Like Google, treat the cut as the end of the file, so any
+		// partial line before it is parsed as it is.
+		p.truncated = lineNum + 1
+		p.report(p.truncated, Warning, FileTooLarge,
+			"file is larger than "+strconv.Itoa(p.maxBytes())+" bytes, and is ignored from here on")
+		err = io.EOF
+	}
+	if err != io.EOF {
+		return err
+	}
+	lineNum++
+	p.reportTruncated(lineNum, truncated)
+	p.parseAndEmitLine(lineNum, last)
+	p.handler.HandleRobotsEnd()
+	return nil
+}
+
+// sliceLines emits each line of robotsBody but the last, as slices of it.
+// It returns the number of lines emitted, the last line, whether that was
+// cut at the maximum line length, and either io.EOF or errBodyTooLarge.
+func (p *Parser) sliceLines(opts Options) (lineNum int, last string, truncated bool, err error) {
+	body := p.robotsBody
+	n := body.len()
+	err = io.EOF
+	if max := p.maxBytes(); max >= 0 && n > max {
+		n, err = max, errBodyTooLarge
+	}
+	maxLen := opts.maxLineLen()
+
+	splitLines(body, n, opts.SkipBOM, func(start, end, next int) {
+		cut := end
+		if maxLen >= 0 && end-start > maxLen {
+			cut = start + maxLen
 		}
-		b = p.robotsBody[cur]
-		if b != utfBOM[i] {
-			break
+		if next == end {
+			// The last line, which is emitted after any FileTooLarge.
+			last, truncated = body.text(start, cut), cut != end
+			return
 		}
-		cur++
-	}
+		lineNum++
+		p.reportTruncated(lineNum, cut != end)
+		p.parseAndEmitLine(lineNum, body.text(start, cut))
+	})
+	return lineNum, last, truncated, err
+}
 
-	lineNum := 0
-	lastWasCarriageReturn := false
-	start := cur
-	end := cur
+// splitLines splits the first n bytes of body into lines, as Parse does,
+// and calls line with the start and end of each, and the end of its line
+// ending. The last line has no line ending, so its end and next are n.
+// If skipBOM is true, a byte order mark, even a partial one, is skipped
+// before the first line.
+func splitLines(body memBody, n int, skipBOM bool, line func(start, end, next int)) {
+	start := 0
+	for skipBOM && start < len(utfBOM) && start < n && body.at(start) == utfBOM[start] {
+		start++
+	}
 	for {
-		if cur == length {
-			break
+		end := body.indexEOL(start, n)
+		if end < 0 {
+			line(start, n, n)
+			return
+		}
+		next := end + 1
+		// The DOS line-ending \r\n is a single line ending.
+		if body.at(end) == 0x0D && next < n && body.at(next) == 0x0A {
+			next++
 		}
-		b = p.robotsBody[cur]
-		cur++
+		line(start, end, next)
+		start = next
+	}
+}
+
+// memBody is a robots.txt body held in memory.
+type memBody interface {
+	len() int
+	at(i int) byte
+	// indexEOL returns the index of the first '\r' or '\n' from i up
+	// to j, or -1 if there is none.
+	indexEOL(i, j int) int
+	// text returns the bytes from i up to j, as a string.
+	text(i, j int) string
+}
+
+// stringBody is a robots.txt body held in a string, whose text is
+// sliced from it without copying.
+type stringBody string
+
+func (s stringBody) len() int             { return len(s) }
+func (s stringBody) at(i int) byte        { return s[i] }
+func (s stringBody) text(i, j int) string { return string(s[i:j]) }
+func (s stringBody) indexEOL(i, j int) int {
+	if k := strings.IndexAny(string(s[i:j]), "\r\n"); k >= 0 {
+		return i + k
+	}
+	return -1
+}
+
+// bytesBody is a robots.txt body held in a byte slice, whose text is
+// copied into a new string for each line.
+type bytesBody []byte
+
+func (b bytesBody) len() int             { return len(b) }
+func (b bytesBody) at(i int) byte        { return b[i] }
+func (b bytesBody) text(i, j int) string { return string(b[i:j]) }
+func (b bytesBody) indexEOL(i, j int) int {
+	if k := bytes.IndexAny(b[i:j], "\r\n"); k >= 0 {
+		return i + k
+	}
+	return -1
+}
+
+// readLines is like sliceLines, but streams the body from reader. It may
+// also return an error from the reader.
+func (p *Parser) readLines(opts Options) (lineNum int, last string, truncated bool, err error) {
+	r := p.reader
+	if max := p.maxBytes(); max >= 0 {
+		r = &limitedByteReader{r: r, n: max}
+	}
+	maxLen := opts.maxLineLen()
+
+	// Skip BOM if present - including partial BOMs.
+	// Afterwards, b holds the first byte that is not part of the BOM.
+	b, err := r.ReadByte()
+	for i := 0; opts.SkipBOM && i < len(utfBOM) && err == nil && b == utfBOM[i]; i++ {
+		b, err = r.ReadByte()
+	}
+
+	lastWasCarriageReturn := false
+	line := make([]byte, 0, 128)
+	for ; err == nil; b, err = r.ReadByte() {
 		if b != 0x0A && b != 0x0D { // Non-line-ending char case.
 			// Add to current line, as long as there's room.
-			if end-start < maxLineLen-1 {
-				end++
+			if maxLen < 0 || len(line) < maxLen {
+				line = append(line, b)
+			} else {
+				truncated = true
 			}
 		} else { // Line-ending character char case.
 			// Only emit an empty line if this was not due to the second character
 			// of the DOS line-ending \r\n .
-			isCRLFContinuation := end == start && lastWasCarriageReturn && b == 0x0A
+			isCRLFContinuation := len(line) == 0 && lastWasCarriageReturn && b == 0x0A
 			if !isCRLFContinuation {
 				lineNum++
-				p.parseAndEmitLine(lineNum, p.robotsBody[start:end])
+				p.reportTruncated(lineNum, truncated)
+				p.parseAndEmitLine(lineNum, string(line))
 			}
-			start = cur
-			end = cur
+			line = line[:0]
+			truncated = false
 			lastWasCarriageReturn = b == 0x0D
 		}
 	}
-	lineNum++
-	p.parseAndEmitLine(lineNum, p.robotsBody[start:end])
-	p.handler.HandleRobotsEnd()
+	return lineNum, string(line), truncated, err
+}
+
+func (p *Parser) maxBytes() int {
+	if p.MaxBytes == 0 {
+		return DefaultMaxBytes
+	}
+	return p.MaxBytes
+}
+
+// errBodyTooLarge is returned by a limitedByteReader after its limit.
+var errBodyTooLarge = errors.New("robots.txt body too large")
+
+// limitedByteReader reads at most n bytes from r. After that, it returns
+// io.EOF if r is also at its end, or else errBodyTooLarge.
+type limitedByteReader struct {
+	r io.ByteReader
+	n int
+}
+
+func (l *limitedByteReader) ReadByte() (byte, error) {
+	if l.n <= 0 {
+		if _, err := l.r.ReadByte(); err != nil {
+			return 0, err
+		}
+		return 0, errBodyTooLarge
+	}
+	l.n--
+	return l.r.ReadByte()
 }
 
 //
@@ -568,6 +1088,32 @@ func Parse(robotsBody string, handler ParseHandler) {
 	parser.Parse()
 }
 
+// ParseBytes is like Parse, but takes the robots.txt body as a byte slice.
+// The body is not copied as a whole, but each line that is parsed is copied
+// into a string, so content after MaxBytes is never copied.
+func ParseBytes(robotsBody []byte, handler ParseHandler) {
+	newParser(bytesBody(robotsBody), handler, optionsOf(nil)).Parse()
+}
+
+// ParseReader is like Parse, but streams the robots.txt body from the
+// given reader, line by line. It returns any error from the reader,
+// other than io.EOF, or else the first *ParseError, as per Parser.Err.
+// After a read error, the handler will have seen the lines read before
+// it, and HandleRobotsEnd is not called.
+func ParseReader(r io.Reader, handler ParseHandler) error {
+	p := newReaderParser(byteReader(r), handler, optionsOf(nil))
+	p.Parse()
+	return p.Err()
+}
+
+// byteReader returns r as an io.ByteReader, buffering it if needed.
+func byteReader(r io.Reader) io.ByteReader {
+	if br, ok := r.(io.ByteReader); ok {
+		return br
+	}
+	return bufio.NewReader(r)
+}
+
 //
 
 // NewRobotsMatcher creates a RobotsMatcher with the default matching strategy. The default
@@ -577,30 +1123,37 @@ func Parse(robotsBody string, handler ParseHandler) {
 // directives. For example, in case of conflicting matches (both Allow and
 // Disallow), the longest match is the one the user wants. For example, in
 // case of a robots.txt file that has the following rules
-//   Allow: /
-//   Disallow: /cgi-bin
+//
+//	Allow: /
+//	Disallow: /cgi-bin
+//
 // it's pretty obvious what the webmaster wants: they want to allow crawl of
 // every URI except /cgi-bin. However, according to the expired internet
 // standard, crawlers should be allowed to crawl everything with such a rule.
-func NewRobotsMatcher() *RobotsMatcher {
+//
+// Options, if given, control which of Google's extensions are used, as
+// per NewParser.
+func NewRobotsMatcher(opts ...Options) *RobotsMatcher {
 	// Line :460
 	m := RobotsMatcher{
 		allow:         newMatchHierarchy(),
 		disallow:      newMatchHierarchy(),
 		MatchStrategy: LongestMatchStrategy{},
+		opts:          optionsOf(opts),
 	}
 	return &m
 }
 
 // init Initialises next path and user-agents to check. Path must contain only the
 // path, params, and query (if any) of the url and must start with a '/'.
-func (m *RobotsMatcher) init(userAgents []string, path string) {
+func (m *RobotsMatcher) init(userAgents []string, path string) error {
 	// Line :478
 	m.path = path
-	if path[0] != '/' {
-		panic("Path must begin with '/'") // TODO Cleanup this panic.
+	if len(path) == 0 || path[0] != '/' {
+		return &ParseError{Kind: InvalidPath}
 	}
 	m.userAgents = userAgents
+	return nil
 }
 
 // AgentsAllowed parses the given robots.txt content, matching it against
@@ -611,8 +1164,28 @@ func (m *RobotsMatcher) init(userAgents []string, path string) {
 // (cannot successfully be parsed by url.Parse).
 func (m *RobotsMatcher) AgentsAllowed(robotsBody string, userAgents []string, uri string) bool {
 	// Line :487
+	return m.agentsAllowed(stringBody(robotsBody), userAgents, uri)
+}
+
+// AgentsAllowedBytes is like AgentsAllowed, but takes the robots.txt
+// content as a byte slice. Like ParseBytes, it copies each line that is
+// parsed, but not the whole body.
+func (m *RobotsMatcher) AgentsAllowedBytes(robotsBody []byte, userAgents []string, uri string) bool {
+	return m.agentsAllowed(bytesBody(robotsBody), userAgents, uri)
+}
+
+// agentsAllowed is AgentsAllowed, for a body held in a string or byte slice.
+func (m *RobotsMatcher) agentsAllowed(robotsBody memBody, userAgents []string, uri string) bool {
 	// The url is not normalized (escaped, percent encoded) here because the user
 	// is asked to provide it in escaped form already.
+	m.err = nil
+	m.truncated = 0
+	parser := newParser(robotsBody, m, m.opts)
+	parser.Strict = m.Strict
+	parser.MaxBytes = m.MaxBytes
+	if m.Fallbacks != nil {
+		userAgents = m.Fallbacks.resolve(userAgents, m.namedAgents(robotsBody))
+	}
 
 	// Departing from Googlebot's behaviour,
 	// and making the API work as expected by Go coders,
@@ -621,16 +1194,59 @@ func (m *RobotsMatcher) AgentsAllowed(robotsBody string, userAgents []string, ur
 	if err != nil {
 		// If the given URI doesn't parse,
 		// we say access is not allowed.
+		m.err = err
 		return false
 	}
-	uri = u.String()
-
-	path := getPathParamsQuery(uri)
-	m.init(userAgents, path)
-	Parse(robotsBody, m)
+	if err := m.init(userAgents, getPathParamsQuery(u.String())); err != nil {
+		m.err = err
+		return false
+	}
+	parser.Parse()
+	m.fail(parser.Err())
+	m.truncated, _ = parser.Truncated()
 	return !m.Disallowed()
 }
 
+// Truncated reports whether the robots.txt body given to the last call to
+// AgentsAllowed or AgentAllowed was cut short at MaxBytes, and if so, at
+// which line. See Parser.Truncated.
+func (m *RobotsMatcher) Truncated() (lineNum int, ok bool) {
+	return m.truncated, m.truncated != 0
+}
+
+// Err returns the first error encountered by the last call to AgentsAllowed
+// or AgentAllowed, or nil if there was none. Errors are either a *ParseError,
+// or a *url.Error if the URI could not be parsed.
+func (m *RobotsMatcher) Err() error {
+	return m.err
+}
+
+func (m *RobotsMatcher) fail(err error) {
+	if m.err == nil {
+		m.err = err
+	}
+}
+
+// normalisedPath returns the path, params and query of the given URI,
+// after normalising it with url.Parse. It returns false if the URI
+// cannot be parsed.
+func normalisedPath(uri string) (string, bool) {
+	path, err := parsePath(uri)
+	return path, err == nil
+}
+
+// parsePath is like normalisedPath, but returns the error from url.Parse.
+func parsePath(uri string) (string, error) {
+	// Departing from Googlebot's behaviour,
+	// and making the API work as expected by Go coders,
+	// we normalise the URI here.
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	return getPathParamsQuery(u.String()), nil
+}
+
 // AgentsAllowed parses the given robots.txt content, matching it against
 // the given userAgents and URI, and returns true if the given URI
 // is allowed to be fetched by any user agent in the list.
@@ -641,6 +1257,12 @@ func AgentsAllowed(robotsBody string, userAgents []string, uri string) bool {
 	return NewRobotsMatcher().AgentsAllowed(robotsBody, userAgents, uri)
 }
 
+// AgentsAllowedBytes is like AgentsAllowed, but takes the robots.txt
+// content as a byte slice.
+func AgentsAllowedBytes(robotsBody []byte, userAgents []string, uri string) bool {
+	return NewRobotsMatcher().AgentsAllowedBytes(robotsBody, userAgents, uri)
+}
+
 // AgentAllowed parses the given robots.txt content, matching it against
 // the given userAgent and URI, and returns true if the given URI
 // is allowed to be fetched by the given user agent.
@@ -662,21 +1284,39 @@ func AgentAllowed(robotsBody string, userAgent string, uri string) bool {
 	return NewRobotsMatcher().AgentAllowed(robotsBody, userAgent, uri)
 }
 
+// AgentAllowedBytes is like AgentAllowed, but takes the robots.txt
+// content as a byte slice.
+func (m *RobotsMatcher) AgentAllowedBytes(robotsBody []byte, userAgent string, uri string) bool {
+	return m.AgentsAllowedBytes(robotsBody, []string{userAgent}, uri)
+}
+
+// AgentAllowedBytes is like AgentAllowed, but takes the robots.txt
+// content as a byte slice.
+func AgentAllowedBytes(robotsBody []byte, userAgent string, uri string) bool {
+	return NewRobotsMatcher().AgentAllowedBytes(robotsBody, userAgent, uri)
+}
+
 // Disallowed returns true if we are disallowed from crawling a matching URI.
 func (m *RobotsMatcher) Disallowed() bool {
 	// Line :506
-	if m.allow.specific.priority > 0 || m.disallow.specific.priority > 0 {
-		return m.disallow.specific.priority > m.allow.specific.priority
+	return disallowed(m.allow, m.disallow, m.everSeenSpecificAgent)
+}
+
+// disallowed decides the verdict for the given allow and disallow matches.
+// It is shared by RobotsMatcher and Robots, so both give identical answers.
+func disallowed(allow, disallow *matchHierarchy, everSeenSpecificAgent bool) bool {
+	if allow.specific.priority > 0 || disallow.specific.priority > 0 {
+		return disallow.specific.priority > allow.specific.priority
 	}
 
-	if m.everSeenSpecificAgent {
+	if everSeenSpecificAgent {
 		// Matching group for user-agent but either without disallow or empty one,
 		// i.e. priority == 0.
 		return false
 	}
 
-	if m.disallow.global.priority > 0 || m.allow.global.priority > 0 {
-		return m.disallow.global.priority > m.allow.global.priority
+	if disallow.global.priority > 0 || allow.global.priority > 0 {
+		return disallow.global.priority > allow.global.priority
 	}
 	return false
 }
@@ -695,10 +1335,15 @@ func (m *RobotsMatcher) DisallowedIgnoreGlobal() bool {
 // MatchingLine returns the line that matched or 0 if none matched.
 func (m *RobotsMatcher) MatchingLine() int {
 	// Line :530
-	if m.everSeenSpecificAgent {
-		return higherPriorityMatch(m.disallow.specific, m.allow.specific).line
+	return matchingLine(m.allow, m.disallow, m.everSeenSpecificAgent)
+}
+
+// matchingLine returns the line of the deciding match, or 0 if none matched.
+func matchingLine(allow, disallow *matchHierarchy, everSeenSpecificAgent bool) int {
+	if everSeenSpecificAgent {
+		return higherPriorityMatch(disallow.specific, allow.specific).line
 	}
-	return higherPriorityMatch(m.disallow.global, m.allow.global).line
+	return higherPriorityMatch(disallow.global, allow.global).line
 }
 
 // EverSeenSpecificAgent returns true iff, when AgentsAllowed() was called,
@@ -725,7 +1370,7 @@ func (m *RobotsMatcher) HandleRobotsStart() {
 // extractUserAgent extracts the matchable part of a user agent string,
 // essentially stopping at the first invalid character.
 // Example: 'Googlebot/2.1' becomes 'Googlebot'
-func (m *RobotsMatcher) extractUserAgent(userAgent string) string {
+func extractUserAgent(userAgent string) string {
 	// Line :552
 	// Allowed characters in user-agent are [a-zA-Z_-].
 
@@ -770,9 +1415,9 @@ func asciiIsSpecial(c byte) bool {
 // isValidUserAgentToObey verifies that the given user agent is valid to be matched against
 // robots.txt. Valid user agent strings only contain the characters
 // [a-zA-Z_-].
-func (m *RobotsMatcher) isValidUserAgentToObey(userAgent string) bool {
+func isValidUserAgentToObey(userAgent string) bool {
 	// Line :562
-	return len(userAgent) > 0 && m.extractUserAgent(userAgent) == userAgent
+	return len(userAgent) > 0 && extractUserAgent(userAgent) == userAgent
 }
 
 // HandleUserAgent is called for every "User-Agent:" line in robots.txt.
@@ -784,13 +1429,10 @@ func (m *RobotsMatcher) HandleUserAgent(lineNum int, userAgent string) {
 		m.seenSeparator = false
 	}
 
-	// Google-specific optimization: a '*' followed by space and more characters
-	// in a user-agent record is still regarded a global rule.
-	if len(userAgent) >= 1 && userAgent[0] == '*' &&
-		(len(userAgent) == 1 || isSpace(userAgent[1])) {
+	userAgent, global := classifyUserAgent(userAgent, m.options(), m.Strict)
+	if global {
 		m.seenGlobalAgent = true
-	} else {
-		userAgent = m.extractUserAgent(userAgent)
+	} else if userAgent != "" {
 		for _, agent := range m.userAgents {
 			if equalsIgnoreCase(userAgent, agent) {
 				m.everSeenSpecificAgent = true
@@ -801,6 +1443,26 @@ func (m *RobotsMatcher) HandleUserAgent(lineNum int, userAgent string) {
 	}
 }
 
+// classifyUserAgent returns the product token that a User-agent value is
+// matched as, or "*" and true if it names the global agent. If strict is
+// true, values that are not product tokens name no agent, and "" is
+// returned. Opts must already be restricted by strict.
+func classifyUserAgent(value string, opts Options, strict bool) (agent string, global bool) {
+	// Google-specific optimization: a '*' followed by space and more characters
+	// in a user-agent record is still regarded a global rule.
+	if len(value) >= 1 && value[0] == '*' &&
+		(len(value) == 1 || isSpace(value[1]) && opts.StarAgentPrefix) {
+		return "*", true
+	}
+	if strict {
+		if !isProductToken(value) {
+			return "", false
+		}
+		return value, false
+	}
+	return extractUserAgent(value), false
+}
+
 func isSpace(c byte) bool {
 	return unicode.IsSpace(rune(c))
 	// return c == ' ' || c == '\t'
@@ -818,6 +1480,9 @@ func (m *RobotsMatcher) HandleAllow(lineNum int, value string) {
 		return
 	}
 	m.seenSeparator = true
+	if m.Strict && !isStrictValue(parsedKey{typ: allowKey}, value) {
+		return
+	}
 	priority := m.MatchStrategy.MatchAllow(m.path, value)
 	if priority >= 0 {
 		if m.seenSpecificAgent {
@@ -826,13 +1491,14 @@ func (m *RobotsMatcher) HandleAllow(lineNum int, value string) {
 			}
 		} else {
 			if !m.seenGlobalAgent {
-				panic("Not seen global agent") // TODO Cleanup this panic.
+				m.fail(&ParseError{Line: lineNum, Kind: RuleOutsideGroup})
+				return
 			}
 			if m.allow.global.priority < priority {
 				m.allow.global.Set(priority, lineNum)
 			}
 		}
-	} else {
+	} else if m.options().NormaliseIndex {
 		// Google-specific optimization: 'index.htm' and 'index.html' are normalized
 		// to '/'.
 		slashPos := strings.LastIndexByte(value, '/')
@@ -851,6 +1517,9 @@ func (m *RobotsMatcher) HandleDisallow(lineNum int, value string) {
 		return
 	}
 	m.seenSeparator = true
+	if m.Strict && !isStrictValue(parsedKey{typ: disallowKey}, value) {
+		return
+	}
 	priority := m.MatchStrategy.MatchDisallow(m.path, value)
 	if priority >= 0 {
 		if m.seenSpecificAgent {
@@ -859,7 +1528,8 @@ func (m *RobotsMatcher) HandleDisallow(lineNum int, value string) {
 			}
 		} else {
 			if !m.seenGlobalAgent {
-				panic("Not seen global agent") // TODO Cleanup this panic.
+				m.fail(&ParseError{Line: lineNum, Kind: RuleOutsideGroup})
+				return
 			}
 			if m.disallow.global.priority < priority {
 				m.disallow.global.Set(priority, lineNum)
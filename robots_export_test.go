@@ -1,5 +1,5 @@
 package grobotstxt
 
 func IsValidUserAgentToObey(userAgent string) bool {
-	return NewRobotsMatcher().isValidUserAgentToObey(userAgent)
+	return isValidUserAgentToObey(userAgent)
 }
@@ -51,6 +51,55 @@ type ParseHandler interface {
 	HandleUnknownAction(lineNum int, action, value string)
 }
 
+// CrawlDelayHandler is implemented by a ParseHandler that handles
+// "Crawl-delay:" lines. Parse() passes Crawl-delay lines to handlers
+// that do not implement it as unknown actions, as it always has.
+type CrawlDelayHandler interface {
+	HandleCrawlDelay(lineNum int, value string)
+}
+
+// RequestRateHandler is implemented by a ParseHandler that handles
+// "Request-rate:" lines, which are otherwise passed as unknown actions.
+type RequestRateHandler interface {
+	HandleRequestRate(lineNum int, value string)
+}
+
+// VisitTimeHandler is implemented by a ParseHandler that handles
+// "Visit-time:" lines, which are otherwise passed as unknown actions.
+type VisitTimeHandler interface {
+	HandleVisitTime(lineNum int, value string)
+}
+
+// ContentSignalHandler is implemented by a ParseHandler that handles
+// "Content-Signal:" lines, which are otherwise passed as unknown actions.
+type ContentSignalHandler interface {
+	HandleContentSignal(lineNum int, value string)
+}
+
+// ContentUsageHandler is implemented by a ParseHandler that handles
+// "Content-Usage:" lines, which are otherwise passed as unknown actions.
+type ContentUsageHandler interface {
+	HandleContentUsage(lineNum int, value string)
+}
+
+// HostHandler is implemented by a ParseHandler that handles Yandex
+// "Host:" lines, which are otherwise passed as unknown actions.
+type HostHandler interface {
+	HandleHost(lineNum int, value string)
+}
+
+// CleanParamHandler is implemented by a ParseHandler that handles Yandex
+// "Clean-param:" lines, which are otherwise passed as unknown actions.
+type CleanParamHandler interface {
+	HandleCleanParam(lineNum int, value string)
+}
+
+// DirectiveHandler is implemented by a ParseHandler that handles the custom
+// directives of Options.Directives, which are otherwise passed as unknown actions.
+type DirectiveHandler interface {
+	HandleDirective(lineNum int, directive Directive, value string)
+}
+
 var _ ParseHandler = &RobotsMatcher{}
 
 // RobotsMatcher — matches robots.txt against URIs.
@@ -64,6 +113,7 @@ var _ ParseHandler = &RobotsMatcher{}
 // robots.txt and the crawl agent.
 //
 // The RobotsMatcher can be re-used for URIs/robots.txt but is not concurrency-safe.
+// For concurrent use, see Compile and Checker.
 type RobotsMatcher struct {
 	// Line :87
 
@@ -83,7 +133,31 @@ type RobotsMatcher struct {
 	// The User-Agents we are interested in.
 	userAgents []string
 
+	// First error seen by AgentsAllowed.
+	err error
+
+	// Line at which the last robots.txt body was cut short, or 0.
+	truncated int
+
+	opts Options // See NewRobotsMatcher.
+
 	MatchStrategy MatchStrategy
+
+	// Strict selects strict RFC 9309 parsing and matching, without
+	// Google's extensions, whatever the matcher's Options. See Parser.Strict.
+	// Compile, and Checker, do not support Strict.
+	Strict bool
+
+	// MaxBytes limits how much of the robots.txt body is parsed.
+	// See Parser.MaxBytes.
+	MaxBytes int
+
+	// Fallbacks, if non-nil, gives the user agents to use instead, when the
+	// robots.txt file has no group for the user agents being matched.
+	// For example, with DefaultFallbacks, Googlebot-Image obeys the
+	// Googlebot groups if there are no Googlebot-Image groups.
+	// Matching with Fallbacks parses the robots.txt body twice.
+	Fallbacks *Fallbacks
 }
 
 func (m *RobotsMatcher) seenAnyAgent() bool {
@@ -64,11 +64,22 @@ var _ = Describe("Robots private", func() {
 		TestPath("//a/b/c", "/b/c")
 	})
 
+	It("should report invalid paths instead of panicking", func() {
+		m := NewRobotsMatcher()
+		err := m.init([]string{"FooBot"}, "")
+		Expect(err).To(Equal(&ParseError{Kind: InvalidPath}))
+		err = m.init([]string{"FooBot"}, "a/b")
+		Expect(err).To(Equal(&ParseError{Kind: InvalidPath}))
+		Expect(m.init([]string{"FooBot"}, "/a/b")).To(Succeed())
+	})
+
 	It("should TestMaybeEscapePattern", func() {
 		TestEscape("http://www.example.com", "http://www.example.com")
 		TestEscape("/a/b/c", "/a/b/c")
 		TestEscape("á", "%C3%A1")
 		TestEscape("%aa", "%AA")
+		TestEscape("%2f", "%2F")
+		TestEscape("/a%2fb%c3%a1", "/a%2Fb%C3%A1")
 	})
 
 })
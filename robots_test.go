@@ -34,15 +34,25 @@ import (
 var _ = Describe("Robots", func() {
 
 	// Line :30
+	// Every verdict is also checked against a compiled Robots,
+	// which must always agree with RobotsMatcher.
 	IsUserAgentAllowed := func(robotstxt, userAgent, url string) bool {
 		matcher := grobotstxt.NewRobotsMatcher()
-		return matcher.AgentAllowed(robotstxt, userAgent, url)
+		allowed := matcher.AgentAllowed(robotstxt, userAgent, url)
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Allowed(userAgent, url)).To(Equal(allowed))
+		return allowed
 	}
 
 	AllowedByRobots := func(robotstxt, userAgents, url string) bool {
 		userAgentList := strings.Split(userAgents, ",")
 		matcher := grobotstxt.NewRobotsMatcher()
-		return matcher.AgentsAllowed(robotstxt, userAgentList, url)
+		allowed := matcher.AgentsAllowed(robotstxt, userAgentList, url)
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.AgentsAllowed(userAgentList, url)).To(Equal(allowed))
+		return allowed
 	}
 
 	EXPECT_TRUE := func(b bool) {
@@ -874,6 +884,37 @@ var _ = Describe("Robots", func() {
 		}()
 	})
 
+	// Percent-escapes in patterns are uppercased, without changing digits,
+	// so "%2f" matches "%2F" in the URI.
+	It("should normalise percent-escapes containing digits", func() {
+		const robotstxt = "User-agent: FooBot\n" +
+			"Disallow: /a%2fb\n" +
+			"Disallow: /%e3%83%84\n"
+		EXPECT_FALSE(IsUserAgentAllowed(robotstxt, "FooBot", "http://foo.bar/a%2Fb"))
+		EXPECT_FALSE(IsUserAgentAllowed(robotstxt, "FooBot", "http://foo.bar/ツ"))
+		EXPECT_TRUE(IsUserAgentAllowed(robotstxt, "FooBot", "http://foo.bar/a/b"))
+	})
+
+	// Values that start with '*', but are not the global agent, have
+	// the token "*", which only names a user agent called "*".
+	It("should not treat other agents starting with '*' as global", func() {
+		for _, agent := range []string{"*/1.0", "*é", "*(x)"} {
+			robotstxt := "User-agent: " + agent + "\n" +
+				"Disallow: /\n"
+			EXPECT_TRUE(IsUserAgentAllowed(robotstxt, "FooBot", "http://foo.bar/a"))
+			EXPECT_FALSE(IsUserAgentAllowed(robotstxt, "*", "http://foo.bar/a"))
+
+			robotstxt = "User-agent: *\n" +
+				"Allow: /\n" +
+				"\n" +
+				"User-agent: FooBot\n" +
+				"User-agent: " + agent + "\n" +
+				"Disallow: /\n"
+			EXPECT_TRUE(IsUserAgentAllowed(robotstxt, "BarBot", "http://foo.bar/a"))
+			EXPECT_FALSE(IsUserAgentAllowed(robotstxt, "FooBot", "http://foo.bar/a"))
+		}
+	})
+
 })
 
 type robotsStatsReporter struct {
@@ -1,5 +1,7 @@
 package grobotstxt
 
+import "io"
+
 type sitemapExtractor struct {
 	sitemaps []string
 }
@@ -14,6 +16,23 @@ func Sitemaps(robotsBody string) []string {
 	return (&sitemapExtractor{}).Sitemaps(robotsBody)
 }
 
+// SitemapsBytes is like Sitemaps, but takes the robots.txt content as a byte slice.
+func SitemapsBytes(robotsBody []byte) []string {
+	f := &sitemapExtractor{}
+	ParseBytes(robotsBody, f)
+	return f.sitemaps
+}
+
+// SitemapsReader is like Sitemaps, but streams the robots.txt content from
+// the given reader. It returns any error from the reader, other than io.EOF.
+func SitemapsReader(r io.Reader) ([]string, error) {
+	f := &sitemapExtractor{}
+	if err := ParseReader(r, f); err != nil {
+		return nil, err
+	}
+	return f.sitemaps, nil
+}
+
 func (f *sitemapExtractor) HandleRobotsStart() {
 	f.sitemaps = nil
 }
@@ -0,0 +1,228 @@
+package grobotstxt
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+// NodeKind denotes the kind of a line in a Tree.
+type NodeKind int
+
+// Kinds of Node.
+const (
+	BlankNode     NodeKind = iota // BlankNode for empty and whitespace-only lines.
+	CommentNode                   // CommentNode for lines with only a comment.
+	DirectiveNode                 // DirectiveNode for key/value lines, known or unknown.
+	InvalidNode                   // InvalidNode for lines the parser ignores.
+)
+
+// String returns the name of the kind of node.
+func (k NodeKind) String() string {
+	switch k {
+	case BlankNode:
+		return "blank"
+	case CommentNode:
+		return "comment"
+	case DirectiveNode:
+		return "directive"
+	default:
+		return "invalid"
+	}
+}
+
+// Span is a range of bytes within a robots.txt body, from Start up to
+// but not including End.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Node is a single line of a robots.txt file. A line is split into the
+// fields below, which, concatenated in order, reproduce the line exactly.
+type Node struct {
+	Kind NodeKind
+
+	// Line and Offset are the line number, as passed to ParseHandler, and
+	// the offset of the start of the line within the robots.txt body, as
+	// parsed. They are not updated when the tree is edited.
+	Line   int
+	Offset int
+
+	Indent string // Leading whitespace.
+
+	// Key, Separator and Value are only set for DirectiveNode, except that
+	// Value holds the text of the line for InvalidNode.
+	Key       string // Key as written, e.g. "DISALOW".
+	Separator string // Text between key and value, e.g. ": " or " ".
+	Value     string // Value as written, before it is escaped by the parser.
+
+	Space    string // Whitespace before the comment, if any.
+	Comment  string // Comment, including its '#', if any.
+	Overflow string // Text beyond the maximum line length, which the parser ignores.
+	EOL      string // Line ending: "\n", "\r\n", "\r", or "" for the last line.
+}
+
+// String returns the text of the line, including its line ending.
+func (n *Node) String() string {
+	return n.Indent + n.Key + n.Separator + n.Value + n.Space + n.Comment + n.Overflow + n.EOL
+}
+
+// Span returns the span of the line, excluding its line ending.
+func (n *Node) Span() Span {
+	return Span{n.Offset, n.Offset + len(n.String()) - len(n.EOL)}
+}
+
+// KeySpan returns the span of the line's key.
+func (n *Node) KeySpan() Span {
+	start := n.Offset + len(n.Indent)
+	return Span{start, start + len(n.Key)}
+}
+
+// ValueSpan returns the span of the line's value.
+func (n *Node) ValueSpan() Span {
+	start := n.KeySpan().End + len(n.Separator)
+	return Span{start, start + len(n.Value)}
+}
+
+// CommentSpan returns the span of the line's comment.
+func (n *Node) CommentSpan() Span {
+	start := n.ValueSpan().End + len(n.Space)
+	return Span{start, start + len(n.Comment)}
+}
+
+// CanonicalKey returns the standard spelling of a directive's key, e.g.
+// "Disallow" for "DISALOW". It returns an empty string for unknown keys,
+// as read by a Parser created without Options, and for nodes that are
+// not directives.
+func (n *Node) CanonicalKey() string {
+	if n.Kind != DirectiveNode {
+		return ""
+	}
+	return keyNames[parseKey(n.Key).Type()]
+}
+
+// ParsedValue returns a directive's value as the parser passes it to a
+// ParseHandler: escaped, for keys whose values are patterns.
+func (n *Node) ParsedValue() string {
+	if n.Kind != DirectiveNode {
+		return ""
+	}
+	if needEscapeValueForKey(parseKey(n.Key)) {
+		return escapePattern(n.Value)
+	}
+	return n.Value
+}
+
+// Tree is a lossless syntax tree of a robots.txt file. It keeps every
+// line, including comments, blank lines and lines the parser ignores,
+// so that the file can be edited, and printed back.
+type Tree struct {
+	BOM   string  // UTF-8 byte order mark, or part of one, that the parser skips.
+	Nodes []*Node // One node per line, as numbered by the parser.
+
+	// Overflow is the content after the first DefaultMaxBytes bytes,
+	// which the parser ignores. The last node holds the part of its line
+	// before the cut.
+	Overflow string
+}
+
+// ParseTree parses the given robots.txt content into a Tree. The tree's
+// String method returns the given content exactly, and its Walk method
+// emits the same callbacks as Parse.
+func ParseTree(robotsBody string) *Tree {
+	t := &Tree{}
+	if len(robotsBody) > DefaultMaxBytes {
+		robotsBody, t.Overflow = robotsBody[:DefaultMaxBytes], robotsBody[DefaultMaxBytes:]
+	}
+	p := &Parser{opts: optionsOf(nil)}
+	splitLines(stringBody(robotsBody), len(robotsBody), p.opts.SkipBOM, func(start, end, next int) {
+		if len(t.Nodes) == 0 {
+			t.BOM = robotsBody[:start]
+		}
+		t.addLine(p, start, robotsBody[start:end], robotsBody[end:next])
+	})
+	return t
+}
+
+func (t *Tree) addLine(p *Parser, offset int, text, eol string) {
+	n := &Node{
+		Line:   len(t.Nodes) + 1,
+		Offset: offset,
+		EOL:    eol,
+	}
+	t.Nodes = append(t.Nodes, n)
+
+	if maxLen := p.opts.maxLineLen(); maxLen >= 0 && len(text) > maxLen {
+		n.Overflow = text[maxLen:]
+		text = text[:maxLen]
+	}
+	content := text
+	if comment := strings.IndexByte(text, '#'); comment != -1 {
+		content = text[:comment]
+		n.Comment = text[comment:]
+	}
+	trimmed := strings.TrimLeftFunc(content, unicode.IsSpace)
+	n.Indent = content[:len(content)-len(trimmed)]
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	n.Space = content[len(n.Indent)+len(trimmed):]
+
+	if trimmed == "" {
+		if n.Comment == "" {
+			n.Kind = BlankNode
+		} else {
+			n.Kind = CommentNode
+		}
+		return
+	}
+	key, value, ok := p.parseKeyAndValue(n.Line, text)
+	if !ok {
+		n.Kind = InvalidNode
+		n.Value = trimmed
+		return
+	}
+	// The key is a prefix of the trimmed text, and the value is a suffix.
+	n.Kind = DirectiveNode
+	n.Key = key
+	n.Value = value
+	n.Separator = trimmed[len(key) : len(trimmed)-len(value)]
+}
+
+// String returns the robots.txt content of the tree.
+func (t *Tree) String() string {
+	var sb strings.Builder
+	t.WriteTo(&sb)
+	return sb.String()
+}
+
+// WriteTo writes the robots.txt content of the tree to w.
+func (t *Tree) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, t.BOM)
+	total := int64(n)
+	for _, node := range t.Nodes {
+		if err != nil {
+			break
+		}
+		n, err = io.WriteString(w, node.String())
+		total += int64(n)
+	}
+	if err == nil {
+		n, err = io.WriteString(w, t.Overflow)
+		total += int64(n)
+	}
+	return total, err
+}
+
+// Walk emits the tree's directives to the given handler, in the same way
+// as Parse does for the robots.txt content. Line numbers are taken from
+// the position of each node within the tree.
+func (t *Tree) Walk(handler ParseHandler) {
+	p := &Parser{handler: handler, opts: optionsOf(nil)}
+	handler.HandleRobotsStart()
+	for i, n := range t.Nodes {
+		if n.Kind == DirectiveNode {
+			p.emit(i+1, n.Key, n.Value)
+		}
+	}
+	handler.HandleRobotsEnd()
+}
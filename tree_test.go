@@ -0,0 +1,144 @@
+package grobotstxt_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// callbackRecorder is a ParseHandler that records every callback it receives.
+type callbackRecorder struct {
+	calls []string
+}
+
+func (r *callbackRecorder) record(format string, a ...interface{}) {
+	r.calls = append(r.calls, fmt.Sprintf(format, a...))
+}
+
+func (r *callbackRecorder) HandleRobotsStart() { r.record("start") }
+func (r *callbackRecorder) HandleRobotsEnd()   { r.record("end") }
+func (r *callbackRecorder) HandleUserAgent(lineNum int, value string) {
+	r.record("%d user-agent %q", lineNum, value)
+}
+func (r *callbackRecorder) HandleAllow(lineNum int, value string) {
+	r.record("%d allow %q", lineNum, value)
+}
+func (r *callbackRecorder) HandleDisallow(lineNum int, value string) {
+	r.record("%d disallow %q", lineNum, value)
+}
+func (r *callbackRecorder) HandleSitemap(lineNum int, value string) {
+	r.record("%d sitemap %q", lineNum, value)
+}
+func (r *callbackRecorder) HandleUnknownAction(lineNum int, action, value string) {
+	r.record("%d %q %q", lineNum, action, value)
+}
+
+var _ = Describe("Tree", func() {
+
+	const robotstxt = "\xEF\xBB\xBF# Header comment.\r\n" +
+		"\r\n" +
+		"  User-agent : FooBot  # Trailing.\r\n" +
+		"DISALOW /säle/\r" +
+		"Allow:\t/x/\n" +
+		"not a directive\n" +
+		"Sitemap: http://foo.bar/sitemap.xml"
+
+	It("should keep every line", func() {
+		tree := grobotstxt.ParseTree(robotstxt)
+		Expect(tree.BOM).To(Equal("\xEF\xBB\xBF"))
+		Expect(tree.Nodes).To(HaveLen(7))
+
+		Expect(tree.Nodes[0]).To(Equal(&grobotstxt.Node{
+			Kind: grobotstxt.CommentNode, Line: 1, Offset: 3,
+			Comment: "# Header comment.", EOL: "\r\n",
+		}))
+		Expect(tree.Nodes[1]).To(Equal(&grobotstxt.Node{
+			Kind: grobotstxt.BlankNode, Line: 2, Offset: 22, EOL: "\r\n",
+		}))
+		Expect(tree.Nodes[2]).To(Equal(&grobotstxt.Node{
+			Kind: grobotstxt.DirectiveNode, Line: 3, Offset: 24,
+			Indent: "  ", Key: "User-agent", Separator: " : ", Value: "FooBot",
+			Space: "  ", Comment: "# Trailing.", EOL: "\r\n",
+		}))
+		Expect(tree.Nodes[3]).To(Equal(&grobotstxt.Node{
+			Kind: grobotstxt.DirectiveNode, Line: 4, Offset: 60,
+			Key: "DISALOW", Separator: " ", Value: "/säle/", EOL: "\r",
+		}))
+		Expect(tree.Nodes[4].Separator).To(Equal(":\t"))
+		Expect(tree.Nodes[5]).To(Equal(&grobotstxt.Node{
+			Kind: grobotstxt.InvalidNode, Line: 6, Offset: 87,
+			Value: "not a directive", EOL: "\n",
+		}))
+		Expect(tree.Nodes[6].EOL).To(Equal(""))
+	})
+
+	It("should give spans within the body", func() {
+		tree := grobotstxt.ParseTree(robotstxt)
+		text := func(s grobotstxt.Span) string {
+			return robotstxt[s.Start:s.End]
+		}
+		n := tree.Nodes[2]
+		Expect(text(n.Span())).To(Equal("  User-agent : FooBot  # Trailing."))
+		Expect(text(n.KeySpan())).To(Equal("User-agent"))
+		Expect(text(n.ValueSpan())).To(Equal("FooBot"))
+		Expect(text(n.CommentSpan())).To(Equal("# Trailing."))
+		Expect(text(tree.Nodes[3].ValueSpan())).To(Equal("/säle/"))
+	})
+
+	It("should map directives to their parsed meaning", func() {
+		tree := grobotstxt.ParseTree(robotstxt)
+		Expect(tree.Nodes[3].CanonicalKey()).To(Equal("Disallow"))
+		Expect(tree.Nodes[3].ParsedValue()).To(Equal("/s%C3%A4le/"))
+		Expect(tree.Nodes[6].ParsedValue()).To(Equal("http://foo.bar/sitemap.xml"))
+		Expect(tree.Nodes[5].CanonicalKey()).To(Equal(""))
+	})
+
+	check := func(robotstxt string) {
+		tree := grobotstxt.ParseTree(robotstxt)
+		Expect(tree.String()).To(Equal(robotstxt))
+
+		expected := &callbackRecorder{}
+		grobotstxt.Parse(robotstxt, expected)
+		walked := &callbackRecorder{}
+		tree.Walk(walked)
+		Expect(walked.calls).To(Equal(expected.calls), "%q", robotstxt)
+	}
+
+	It("should print back identically, and walk like Parse", func() {
+		check(robotstxt)
+		for _, robotstxt := range adversarialRobots {
+			check(robotstxt)
+		}
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 500; i++ {
+			var sb strings.Builder
+			for n := rnd.Intn(40); n > 0; n-- {
+				sb.WriteString(fragments[rnd.Intn(len(fragments))])
+			}
+			check(sb.String())
+		}
+	})
+
+	It("should not parse content after DefaultMaxBytes", func() {
+		robotstxt := "User-agent: *\nDisallow: /x\n" +
+			strings.Repeat("\n", 600*1024) +
+			"Disallow: /y\n"
+		check(robotstxt)
+		tree := grobotstxt.ParseTree(robotstxt)
+		Expect(tree.Overflow).To(HaveLen(len(robotstxt) - grobotstxt.DefaultMaxBytes))
+
+		check("User-agent: *\nDisallow: /x\n" + strings.Repeat("#", grobotstxt.DefaultMaxBytes) + "\nDisallow: /y\n")
+		check("User-agent: *\n" + strings.Repeat("\r", grobotstxt.DefaultMaxBytes-14) + "\r\nDisallow: /y\n")
+	})
+
+	It("should print edits", func() {
+		tree := grobotstxt.ParseTree("User-agent: *\nDisallow: /x/ # Old.\n")
+		tree.Nodes[1].Value = "/y/"
+		Expect(tree.String()).To(Equal("User-agent: *\nDisallow: /y/ # Old.\n"))
+	})
+
+})
@@ -0,0 +1,118 @@
+package grobotstxt
+
+import "strings"
+
+// Preferences maps usage categories to whether a site permits them,
+// e.g. {"search": true, "ai-train": false}. Categories are lowercase, and
+// are kept as written, so the Content-Signal category "ai-train" and the
+// Content-Usage category "train-ai" are distinct.
+type Preferences map[string]bool
+
+// UsageRule is a single "Content-Signal:" or "Content-Usage:" line,
+// which states a site's preferences for how its content may be used,
+// for example by AI systems.
+//
+// For example, the lines
+//
+//	Content-Signal: search=yes, ai-train=no
+//	Content-Usage: /blog/ train-ai=n
+//
+// have Preferences {"search": true, "ai-train": false} for all paths,
+// and {"train-ai": false} for paths matching "/blog/".
+//
+// See https://contentsignals.org and
+// https://datatracker.ietf.org/wg/aipref/about/
+type UsageRule struct {
+	// Pattern is the path pattern that the line applies to, as escaped by
+	// the parser. If empty, the line applies to all paths.
+	Pattern     string
+	Preferences Preferences
+	Line        int
+}
+
+// parseUsageRule parses the value of a "Content-Signal:" or "Content-Usage:"
+// line, which has the form [pattern] category=value[, category=value...].
+// Values may be yes/y or no/n. Invalid entries are skipped, and false is
+// returned if there are no valid entries.
+func parseUsageRule(lineNum int, value string) (UsageRule, bool) {
+	r := UsageRule{
+		Preferences: Preferences{},
+		Line:        lineNum,
+	}
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "/") || strings.HasPrefix(value, "*") {
+		end := strings.IndexAny(value, " \t")
+		if end == -1 {
+			return UsageRule{}, false
+		}
+		r.Pattern = value[:end]
+		value = value[end:]
+	}
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		eq := strings.IndexByte(entry, '=')
+		if eq <= 0 {
+			continue
+		}
+		category := strings.ToLower(entry[:eq])
+		switch strings.ToLower(strings.Trim(entry[eq+1:], `"`)) {
+		case "yes", "y":
+			r.Preferences[category] = true
+		case "no", "n":
+			r.Preferences[category] = false
+		}
+	}
+	if len(r.Preferences) == 0 {
+		return UsageRule{}, false
+	}
+	return r, true
+}
+
+// UsagePreferences parses the given robots.txt content, and returns the
+// usage preferences that apply to the given userAgent and URI.
+// See Checker.UsagePreferences.
+func UsagePreferences(robotsBody string, userAgent string, uri string) Preferences {
+	c, err := NewChecker(robotsBody, userAgent)
+	if err != nil {
+		return nil
+	}
+	return c.UsagePreferences(uri)
+}
+
+// UsagePreferences returns the usage preferences that apply to the given
+// URI for the Checker's user agents. The usage rules are taken from the same
+// groups as CrawlDelay.
+//
+// For each category, the rule with the longest matching pattern is used,
+// in the same way as for Allow and Disallow. A rule without a pattern has
+// the lowest priority. Categories that no rule mentions are absent from
+// the result.
+//
+// UsagePreferences returns nil if the given URI is invalid
+// (cannot successfully be parsed by url.Parse).
+func (c *Checker) UsagePreferences(uri string) Preferences {
+	path, ok := normalisedPath(uri)
+	if !ok {
+		return nil
+	}
+	prefs := Preferences{}
+	priorities := make(map[string]int)
+	for _, r := range c.directives.usageRules {
+		priority := 0
+		if r.Pattern != "" {
+			priority = c.profile.MatchStrategy.MatchAllow(path, r.Pattern)
+			if priority < 0 {
+				continue
+			}
+		}
+		for category, allowed := range r.Preferences {
+			if p, seen := priorities[category]; seen && p >= priority {
+				continue
+			}
+			priorities[category] = priority
+			prefs[category] = allowed
+		}
+	}
+	return prefs
+}
@@ -0,0 +1,61 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UsagePreferences", func() {
+
+	const robotstxt = "Content-Signal: ai-train=no\n" +
+		"\n" +
+		"User-agent: *\n" +
+		"Content-Signal: search=yes, ai-train=no, ai-input=yes\n" +
+		"Content-Usage: /blog/ train-ai=y\n" +
+		"Content-Usage: /blog/drafts/ train-ai=n\n" +
+		"Content-Signal: nonsense\n" +
+		"Allow: /\n" +
+		"\n" +
+		"User-agent: FooBot\n" +
+		"Content-Usage: train-ai=n, search=y\n" +
+		"Disallow: /private/\n"
+
+	It("should scope preferences to groups", func() {
+		Expect(grobotstxt.UsagePreferences(robotstxt, "FooBot", "http://foo.bar/blog/")).To(Equal(grobotstxt.Preferences{
+			"train-ai": false,
+			"search":   true,
+		}))
+		Expect(grobotstxt.UsagePreferences(robotstxt, "BarBot", "http://foo.bar/")).To(Equal(grobotstxt.Preferences{
+			"search":   true,
+			"ai-train": false,
+			"ai-input": true,
+		}))
+	})
+
+	It("should scope preferences to paths", func() {
+		Expect(grobotstxt.UsagePreferences(robotstxt, "BarBot", "http://foo.bar/blog/post")).To(Equal(grobotstxt.Preferences{
+			"search":   true,
+			"ai-train": false,
+			"ai-input": true,
+			"train-ai": true,
+		}))
+		Expect(grobotstxt.UsagePreferences(robotstxt, "BarBot", "http://foo.bar/blog/drafts/post")).To(HaveKeyWithValue("train-ai", false))
+	})
+
+	It("should return nothing without preferences", func() {
+		Expect(grobotstxt.UsagePreferences("", "FooBot", "http://foo.bar/")).To(BeEmpty())
+		Expect(grobotstxt.UsagePreferences(robotstxt, "FooBot", "http://foo.bar/%zz")).To(BeNil())
+	})
+
+	It("should expose usage rules on groups", func() {
+		robots, err := grobotstxt.Compile(robotstxt)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(robots.Groups()[0].UsageRules).To(Equal([]grobotstxt.UsageRule{
+			{Preferences: grobotstxt.Preferences{"search": true, "ai-train": false, "ai-input": true}, Line: 4},
+			{Pattern: "/blog/", Preferences: grobotstxt.Preferences{"train-ai": true}, Line: 5},
+			{Pattern: "/blog/drafts/", Preferences: grobotstxt.Preferences{"train-ai": false}, Line: 6},
+		}))
+	})
+
+})
@@ -0,0 +1,202 @@
+package grobotstxt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Product is a product of an HTTP User-Agent header, such as "Googlebot/2.1".
+type Product struct {
+	Name    string
+	Version string // Version, if any, without the '/'.
+
+	// InComment is true if the product was found inside a comment, such as
+	// "(compatible; Googlebot/2.1)", where crawlers often name themselves.
+	InComment bool
+}
+
+// String returns the product in the form "Name/Version".
+func (p Product) String() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return p.Name + "/" + p.Version
+}
+
+// ParseUserAgent parses an HTTP User-Agent header, as per RFC 7231, and
+// returns its products, in order. Products are also taken from the words
+// of comments, which are separated by ';' or whitespace. Text that is not
+// a product, such as a URL, is skipped.
+//
+// See https://httpwg.org/specs/rfc7231.html#header.user-agent
+func ParseUserAgent(header string) []Product {
+	var products []Product
+	for i := 0; i < len(header); {
+		c := header[i]
+		switch {
+		case c == '(':
+			var comment string
+			comment, i = readComment(header, i)
+			products = append(products, commentProducts(comment)...)
+		case asciiIsTokenChar(c):
+			var p Product
+			p, i = readProduct(header, i)
+			products = append(products, p)
+		default:
+			i++
+		}
+	}
+	return products
+}
+
+// readProduct reads a product starting at s[i], and returns it,
+// and the index after it.
+func readProduct(s string, i int) (Product, int) {
+	start := i
+	for i < len(s) && asciiIsTokenChar(s[i]) {
+		i++
+	}
+	p := Product{Name: s[start:i]}
+	if i < len(s) && s[i] == '/' {
+		i++
+		start = i
+		for i < len(s) && asciiIsTokenChar(s[i]) {
+			i++
+		}
+		p.Version = s[start:i]
+	}
+	return p, i
+}
+
+// readComment reads a comment starting with the '(' at s[i], and returns
+// its text, without the outer parentheses, and the index after it.
+// Nested comments are kept in the text. An unterminated comment runs to
+// the end of s.
+func readComment(s string, i int) (string, int) {
+	start := i + 1
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++ // Quoted pair.
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[start:i], i + 1
+			}
+		}
+	}
+	return s[start:], len(s)
+}
+
+// commentProducts returns the products in the words of a comment.
+func commentProducts(comment string) []Product {
+	var products []Product
+	words := strings.FieldsFunc(comment, func(r rune) bool {
+		return r == ';' || r == ',' || r == '(' || r == ')' || r < 0x80 && isSpace(byte(r))
+	})
+	for _, word := range words {
+		if !asciiIsTokenChar(word[0]) {
+			continue
+		}
+		p, end := readProduct(word, 0)
+		if end < len(word) {
+			// Not a product, such as "+http://www.google.com/bot.html".
+			continue
+		}
+		p.InComment = true
+		products = append(products, p)
+	}
+	return products
+}
+
+// Crawlers is a registry of the robots.txt product tokens of known crawlers,
+// used to find which robots.txt groups apply to an HTTP request.
+//
+// Crawlers is safe for concurrent use by multiple goroutines.
+type Crawlers struct {
+	mu     sync.RWMutex
+	tokens map[string]string // Lower case token, to token.
+}
+
+// NewCrawlers returns a registry of the given crawler tokens.
+// It panics if any token is invalid, as per Register.
+func NewCrawlers(tokens ...string) *Crawlers {
+	c := &Crawlers{tokens: make(map[string]string)}
+	if err := c.Register(tokens...); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// DefaultCrawlers holds the product tokens of well-known crawlers.
+// The registry is not exhaustive, and more can be registered.
+var DefaultCrawlers = NewCrawlers(
+	// Google.
+	"Googlebot", "Googlebot-Image", "Googlebot-News", "Googlebot-Video",
+	"Storebot-Google", "AdsBot-Google", "AdsBot-Google-Mobile",
+	"Mediapartners-Google", "APIs-Google", "FeedFetcher-Google",
+	// Microsoft.
+	"bingbot", "msnbot", "BingPreview", "adidxbot",
+	// Yandex.
+	"Yandex", "YandexBot", "YandexImages", "YandexVideo", "YandexNews", "YandexMobileBot",
+	// Others.
+	"Applebot", "Baiduspider", "DuckDuckBot", "Slurp", "Sogou", "Exabot",
+	"facebookexternalhit", "Twitterbot", "LinkedInBot", "Pinterestbot",
+	"AhrefsBot", "SemrushBot", "MJ12bot", "PetalBot", "SeznamBot",
+	"archive.org_bot", "ia_archiver",
+	// AI crawlers.
+	"GPTBot", "ChatGPT-User", "OAI-SearchBot", "ClaudeBot", "Claude-User",
+	"Claude-SearchBot", "anthropic-ai", "CCBot", "PerplexityBot",
+	"Bytespider", "Amazonbot", "meta-externalagent",
+)
+
+// Register adds the given product tokens to the registry. Tokens must be
+// valid, such as "FooBot" or "FooBot-Image", and are matched ignoring case.
+func (c *Crawlers) Register(tokens ...string) error {
+	for _, token := range tokens {
+		if token == "*" || !isValidUserAgentToObey(token) {
+			return fmt.Errorf("grobotstxt: invalid user-agent %q", token)
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, token := range tokens {
+		c.tokens[strings.ToLower(token)] = token
+	}
+	return nil
+}
+
+// Known returns true if the given token is registered, ignoring case.
+func (c *Crawlers) Known(token string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.tokens[strings.ToLower(token)]
+	return ok
+}
+
+// Match parses the given HTTP User-Agent header, and returns the registered
+// tokens it names, in order and without duplicates, spelled as registered.
+// The result can be passed to AgentsAllowed. It returns nil if the header
+// names no known crawler.
+//
+// For example, for Googlebot's header, "Mozilla/5.0 (compatible;
+// Googlebot/2.1; +http://www.google.com/bot.html)", Match returns
+// ["Googlebot"].
+func (c *Crawlers) Match(header string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, p := range ParseUserAgent(header) {
+		key := strings.ToLower(p.Name)
+		if token, ok := c.tokens[key]; ok && !seen[key] {
+			seen[key] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,77 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("User-Agent headers", func() {
+
+	It("should parse products and comments", func() {
+		products := grobotstxt.ParseUserAgent("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+		Expect(products).To(Equal([]grobotstxt.Product{
+			{Name: "Mozilla", Version: "5.0"},
+			{Name: "compatible", InComment: true},
+			{Name: "Googlebot", Version: "2.1", InComment: true},
+		}))
+		Expect(products[2].String()).To(Equal("Googlebot/2.1"))
+
+		products = grobotstxt.ParseUserAgent("curl/8.1.2")
+		Expect(products).To(Equal([]grobotstxt.Product{{Name: "curl", Version: "8.1.2"}}))
+
+		// Nested comments, quoted pairs, and unterminated comments.
+		products = grobotstxt.ParseUserAgent(`FooBot/1.0 (a \) (b; BarBot/2) c) (BazBot`)
+		Expect(products).To(Equal([]grobotstxt.Product{
+			{Name: "FooBot", Version: "1.0"},
+			{Name: "a", InComment: true},
+			{Name: "b", InComment: true},
+			{Name: "BarBot", Version: "2", InComment: true},
+			{Name: "c", InComment: true},
+			{Name: "BazBot", InComment: true},
+		}))
+
+		Expect(grobotstxt.ParseUserAgent("")).To(BeEmpty())
+	})
+
+	It("should match known crawlers", func() {
+		c := grobotstxt.DefaultCrawlers
+		for header, expected := range map[string][]string{
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": {"Googlebot"},
+			"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) " +
+				"Chrome/120.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": {"Googlebot"},
+			"Googlebot-Image/1.0": {"Googlebot-Image"},
+			"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)":                                {"bingbot"},
+			"Mozilla/5.0 (compatible; Yahoo! Slurp; http://help.yahoo.com/help/us/ysearch/slurp)":                    {"Slurp"},
+			"Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.2; +https://openai.com/gptbot)": {"GPTBot"},
+			"GOOGLEBOT/2.1 (googlebot)":                                              {"Googlebot"},
+			"AdsBot-Google (+http://www.google.com/adsbot.html)":                     {"AdsBot-Google"},
+			"Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0": nil,
+			"": nil,
+		} {
+			Expect(c.Match(header)).To(Equal(expected), header)
+		}
+	})
+
+	It("should register crawlers", func() {
+		c := grobotstxt.NewCrawlers("FooBot")
+		Expect(c.Known("foobot")).To(BeTrue())
+		Expect(c.Known("BarBot")).To(BeFalse())
+		Expect(c.Register("BarBot", "BazBot-News")).To(Succeed())
+		Expect(c.Match("BarBot/1.0 (FooBot; bazbot-news)")).To(Equal([]string{"BarBot", "FooBot", "BazBot-News"}))
+
+		Expect(c.Register("*")).NotTo(Succeed())
+		Expect(c.Register("Foo Bot")).NotTo(Succeed())
+		Expect(c.Register("")).NotTo(Succeed())
+		Expect(c.Register("QuxBot", "Foo/1.0")).NotTo(Succeed())
+		Expect(c.Known("QuxBot")).To(BeFalse())
+	})
+
+	It("should find the groups for a request", func() {
+		const robotstxt = "User-agent: *\nAllow: /\n\nUser-agent: Googlebot\nDisallow: /\n"
+		header := "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+		agents := grobotstxt.DefaultCrawlers.Match(header)
+		Expect(grobotstxt.AgentsAllowed(robotstxt, agents, "http://foo.bar/x")).To(BeFalse())
+	})
+
+})
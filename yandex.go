@@ -0,0 +1,154 @@
+package grobotstxt
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CleanParam is the value of a Yandex "Clean-param:" line, which lists
+// query parameters that do not affect page content, such as session or
+// referrer ids, and may be removed from URLs when deduplicating them.
+//
+// For example, the line
+//
+//	Clean-param: ref&sid /forum/*.php
+//
+// has Params "ref" and "sid", and Path "/forum/*.php".
+//
+// See https://yandex.com/support/webmaster/robot-workings/clean-param.html
+type CleanParam struct {
+	Params []string
+	// Path is the path prefix that the rule applies to, as escaped by the
+	// parser, and may contain '*' wildcards. If empty, the rule applies
+	// to all paths.
+	Path string
+}
+
+// Matches returns true if the rule applies to the given URI path.
+func (c CleanParam) Matches(path string) bool {
+	return c.Path == "" || Matches(path, c.Path)
+}
+
+// parseCleanParam parses the value of a "Clean-param:" line, which has the
+// form p0[&p1&p2...] [path]. It returns false if the value is not valid.
+func parseCleanParam(value string) (CleanParam, bool) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return CleanParam{}, false
+	}
+	var c CleanParam
+	for _, p := range strings.Split(fields[0], "&") {
+		if p != "" {
+			c.Params = append(c.Params, p)
+		}
+	}
+	if len(c.Params) == 0 {
+		return CleanParam{}, false
+	}
+	if len(fields) == 2 {
+		c.Path = fields[1]
+	}
+	return c, true
+}
+
+// Host parses the given robots.txt content, and returns the value of its
+// first Yandex "Host:" line, which names the site's preferred mirror.
+// It returns an empty string if there is no Host line.
+func Host(robotsBody string) string {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return ""
+	}
+	return r.Host()
+}
+
+// CleanParams parses the given robots.txt content, and returns all of its
+// valid Yandex "Clean-param:" values.
+func CleanParams(robotsBody string) []CleanParam {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return nil
+	}
+	return r.CleanParams()
+}
+
+// CleanURL parses the given robots.txt content, and returns the canonical
+// form of the given URI. See Robots.CleanURL.
+func CleanURL(robotsBody, uri string) (string, error) {
+	r, err := Compile(robotsBody)
+	if err != nil {
+		return "", err
+	}
+	return r.CleanURL(uri)
+}
+
+// Host returns the value of the first Yandex "Host:" line,
+// or an empty string if there is none.
+func (r *Robots) Host() string {
+	if len(r.hosts) == 0 {
+		return ""
+	}
+	return r.hosts[0]
+}
+
+// CleanParams returns all valid Yandex "Clean-param:" values.
+// Like Sitemap, Clean-param lines apply to the whole file,
+// regardless of which group they appear in.
+func (r *Robots) CleanParams() []CleanParam {
+	return r.cleanParams
+}
+
+// CleanURL returns the canonical form of the given URI, as used by Yandex to
+// deduplicate URLs: the query parameters named by every Clean-param rule that
+// applies to the URI's path are removed. The order and encoding of the
+// remaining query parameters are preserved.
+//
+// CleanURL returns an error if the URI cannot be parsed by url.Parse.
+func (r *Robots) CleanURL(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.RawQuery == "" || len(r.cleanParams) == 0 {
+		return u.String(), nil
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	remove := make(map[string]bool)
+	for _, c := range r.cleanParams {
+		if c.Matches(path) {
+			for _, p := range c.Params {
+				remove[queryUnescape(p)] = true
+			}
+		}
+	}
+	if len(remove) == 0 {
+		return u.String(), nil
+	}
+
+	var kept []string
+	for _, kv := range strings.Split(u.RawQuery, "&") {
+		name := kv
+		if i := strings.IndexByte(kv, '='); i != -1 {
+			name = kv[:i]
+		}
+		if !remove[queryUnescape(name)] {
+			kept = append(kept, kv)
+		}
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	u.ForceQuery = false
+	return u.String(), nil
+}
+
+// queryUnescape returns the unescaped form of s,
+// or s itself if it is not validly escaped.
+func queryUnescape(s string) string {
+	if t, err := url.QueryUnescape(s); err == nil {
+		return t
+	}
+	return s
+}
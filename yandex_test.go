@@ -0,0 +1,65 @@
+package grobotstxt_test
+
+import (
+	"github.com/jimsmart/grobotstxt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Yandex", func() {
+
+	const robotstxt = "User-agent: Yandex\n" +
+		"Disallow: /admin/\n" +
+		"Host: https://www.example.com\n" +
+		"Clean-param: s /forum/showthread.php\n" +
+		"Clean-param: sid&sort /forum/*.php\n" +
+		"Clean-param: ref\n" +
+		"Clean-param:\n" +
+		"\n" +
+		"User-agent: *\n" +
+		"Host: example.com\n" +
+		"Clean param: utm_source&utm_medium /news/\n"
+
+	It("should parse Host", func() {
+		Expect(grobotstxt.Host(robotstxt)).To(Equal("https://www.example.com"))
+		Expect(grobotstxt.Host("")).To(BeEmpty())
+		Expect(grobotstxt.Host("Hostname: example.com\nHosts: example.com\n")).To(BeEmpty())
+	})
+
+	It("should parse Clean-param", func() {
+		Expect(grobotstxt.CleanParams(robotstxt)).To(Equal([]grobotstxt.CleanParam{
+			{Params: []string{"s"}, Path: "/forum/showthread.php"},
+			{Params: []string{"sid", "sort"}, Path: "/forum/*.php"},
+			{Params: []string{"ref"}},
+			{Params: []string{"utm_source", "utm_medium"}, Path: "/news/"},
+		}))
+	})
+
+	It("should not change Allow and Disallow verdicts", func() {
+		Expect(grobotstxt.AgentAllowed(robotstxt, "Yandex", "http://www.example.com/admin/")).To(BeFalse())
+		Expect(grobotstxt.AgentAllowed(robotstxt, "Yandex", "http://www.example.com/forum/")).To(BeTrue())
+	})
+
+	It("should clean URLs", func() {
+		clean := func(uri string) string {
+			s, err := grobotstxt.CleanURL(robotstxt, uri)
+			Expect(err).NotTo(HaveOccurred())
+			return s
+		}
+		Expect(clean("http://www.example.com/forum/showthread.php?s=681498b9648949605&t=8243")).
+			To(Equal("http://www.example.com/forum/showthread.php?t=8243"))
+		Expect(clean("http://www.example.com/forum/index.php?sort=asc&id=7&sid=1")).
+			To(Equal("http://www.example.com/forum/index.php?id=7"))
+		Expect(clean("http://www.example.com/forum/index.html?sort=asc&id=7&ref=x")).
+			To(Equal("http://www.example.com/forum/index.html?sort=asc&id=7"))
+		Expect(clean("http://www.example.com/news/a?utm_source=x&utm_medium=y")).
+			To(Equal("http://www.example.com/news/a"))
+		Expect(clean("http://www.example.com/blog/a?utm_source=x&q=%C3%A1")).
+			To(Equal("http://www.example.com/blog/a?utm_source=x&q=%C3%A1"))
+		Expect(clean("http://www.example.com/")).To(Equal("http://www.example.com/"))
+
+		_, err := grobotstxt.CleanURL(robotstxt, "http://www.example.com/%zz")
+		Expect(err).To(HaveOccurred())
+	})
+
+})